@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"zerodelay/internal/config"
+	"zerodelay/internal/database"
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/repository"
+	"zerodelay/internal/service"
+)
+
+const usage = `Usage: importer <command> [options]
+
+Commands:
+  places    Import designated emergency evacuation sites (指定緊急避難場所) from an open-data CSV
+  hazards   Import hazard map polygons (inundation depth/duration, house-collapse, landslide) from a GeoJSON file
+
+Run "importer <command> -h" for command options.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "places":
+		runPlaces(os.Args[2:])
+	case "hazards":
+		runHazards(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func runPlaces(args []string) {
+	fs := flag.NewFlagSet("places", flag.ExitOnError)
+	file := fs.String("file", "", "path to the CSV file (\"-\" for stdin)")
+	dryRun := fs.Bool("dry-run", false, "report inserts/updates/skips without writing")
+	category := fs.String("category", string(model.PlaceCategoryEmergencyEvacuationSite), "category assigned to imported places")
+	verbose := fs.Bool("verbose", false, "print the result of every row")
+	fs.Parse(args)
+
+	if *file == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	src, closeSrc := openInput(*file)
+	defer closeSrc()
+
+	db := connectDatabase()
+	defer db.Close()
+
+	importService := service.NewPlaceImportService(repository.NewPlaceRepository(db.DB))
+	report, err := importService.ImportCSV(src, model.PlaceImportOptions{
+		DryRun:   *dryRun,
+		Category: model.PlaceCategory(*category),
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	for _, row := range report.Rows {
+		if !*verbose && row.Action != model.PlaceImportSkip {
+			continue
+		}
+		if row.Reason != "" {
+			fmt.Printf("line %d\t%s\t%s\t%s (%s)\n", row.Line, row.Action, row.ExternalID, row.Name, row.Reason)
+		} else {
+			fmt.Printf("line %d\t%s\t%s\t%s\n", row.Line, row.Action, row.ExternalID, row.Name)
+		}
+	}
+
+	mode := "applied"
+	if report.DryRun {
+		mode = "dry run"
+	}
+	fmt.Printf("%s: %d rows, %d inserts, %d updates, %d skips\n",
+		mode, report.Total, report.Inserted, report.Updated, report.Skipped)
+}
+
+func runHazards(args []string) {
+	fs := flag.NewFlagSet("hazards", flag.ExitOnError)
+	file := fs.String("file", "", "path to the GeoJSON file (\"-\" for stdin)")
+	source := fs.String("source", "", "dataset identifier; zones of the same source are replaced on re-import (required)")
+	kind := fs.String("kind", string(model.HazardKindInundationDepth), "hazard kind: inundation_depth, inundation_duration, house_collapse or landslide")
+	scenario := fs.String("scenario", "", "rainfall scenario: planned or maximum (default: read from properties)")
+	river := fs.String("river", "", "source river name (default: read from properties)")
+	dryRun := fs.Bool("dry-run", false, "validate the file without writing")
+	allowEmpty := fs.Bool("allow-empty", false, "replace the source's zones even when no feature could be imported (deletes them)")
+	allowSkips := fs.Bool("allow-skips", false, "import even when more than 10% of the features are skipped")
+	fs.Parse(args)
+
+	if *file == "" || *source == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	src, closeSrc := openInput(*file)
+	defer closeSrc()
+
+	db := connectDatabase()
+	defer db.Close()
+
+	hazardService := service.NewHazardService(repository.NewHazardRepository(db.DB))
+	report, err := hazardService.ImportGeoJSON(src, model.HazardImportOptions{
+		Source:      *source,
+		Kind:        model.HazardKind(*kind),
+		Scenario:    model.FloodScenario(*scenario),
+		SourceRiver: *river,
+		DryRun:      *dryRun,
+		AllowEmpty:  *allowEmpty,
+		AllowSkips:  *allowSkips,
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	for _, skip := range report.Skipped {
+		fmt.Printf("feature %d\tskip\t%s\n", skip.Index, skip.Reason)
+	}
+
+	mode := "applied"
+	if report.DryRun {
+		mode = "dry run"
+	}
+	fmt.Printf("%s: source %s, %d features, %d zones imported, %d skipped, %d existing zones replaced\n",
+		mode, report.Source, report.Total, report.Imported, len(report.Skipped), report.Replaced)
+}
+
+// openInput opens the file or stdin ("-")
+func openInput(path string) (io.Reader, func()) {
+	if path == "-" {
+		return os.Stdin, func() {}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", path, err)
+	}
+	return f, func() { f.Close() }
+}
+
+func connectDatabase() *database.DB {
+	cfg := config.Load()
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	return db
+}
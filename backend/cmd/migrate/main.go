@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"zerodelay/internal/config"
+	"zerodelay/internal/database"
+	"zerodelay/migrations"
+)
+
+const usage = `Usage: migrate <command> [options]
+
+Commands:
+  up              Apply all pending migrations
+  down [-steps N] Roll back the latest N applied migrations (default 1)
+                  The baseline is never rolled back unless -drop-baseline is given (drops every table)
+  status          List migrations and whether they have been applied
+  create <name>   Create empty up/down files for a new migration in migrations/
+
+Run from the backend directory. The server applies pending migrations on startup.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		runUp()
+	case "down":
+		runDown(os.Args[2:])
+	case "status":
+		runStatus()
+	case "create":
+		runCreate(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func runUp() {
+	migrator, closeDB := newMigrator()
+	defer closeDB()
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+	if len(applied) == 0 {
+		fmt.Println("No pending migrations")
+		return
+	}
+	for _, m := range applied {
+		fmt.Printf("applied\t%03d_%s\n", m.Version, m.Name)
+	}
+}
+
+func runDown(args []string) {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	dropBaseline := fs.Bool("drop-baseline", false, "also roll back the baseline migration, dropping every table (ALL DATA IS LOST)")
+	fs.Parse(args)
+	if *steps < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	migrator, closeDB := newMigrator()
+	defer closeDB()
+
+	rolledBack, err := migrator.Down(context.Background(), *steps, *dropBaseline)
+	for _, m := range rolledBack {
+		fmt.Printf("rolled back\t%03d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatalf("Rollback failed: %v", err)
+	}
+	if len(rolledBack) == 0 {
+		fmt.Println("No applied migrations")
+	}
+}
+
+func runStatus() {
+	migrator, closeDB := newMigrator()
+	defer closeDB()
+
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to read migration status: %v", err)
+	}
+	for _, s := range statuses {
+		switch {
+		case s.Missing:
+			fmt.Printf("%03d\t(missing file)\tapplied %s\n", s.Version, s.AppliedAt.Format("2006-01-02 15:04:05"))
+		case s.AppliedAt != nil:
+			fmt.Printf("%03d\t%s\tapplied %s\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+		default:
+			fmt.Printf("%03d\t%s\tpending\n", s.Version, s.Name)
+		}
+	}
+}
+
+func runCreate(args []string) {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	dir := fs.String("dir", "migrations", "directory of the migration files")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: migrate create [-dir migrations] <name>")
+		os.Exit(2)
+	}
+
+	upPath, downPath, err := database.CreateMigration(*dir, fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to create migration: %v", err)
+	}
+	fmt.Println(upPath)
+	fmt.Println(downPath)
+}
+
+func newMigrator() (*database.Migrator, func()) {
+	cfg := config.Load()
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		db.Close()
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	return migrator, func() { db.Close() }
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"zerodelay/internal/config"
+	"zerodelay/internal/database"
+	"zerodelay/internal/domain/model"
+	domainrepository "zerodelay/internal/domain/repository"
+	"zerodelay/internal/mail"
+	"zerodelay/internal/repository"
+	"zerodelay/internal/service"
+)
+
+// reconcile diffs the auth provider's users (Firebase or the local provider) against the users table,
+// creates missing rows, syncs changed emails and flags rows whose auth user no longer exists.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing")
+	verbose := flag.Bool("verbose", false, "print every change, not only skipped users")
+	flag.Parse()
+
+	cfg := config.Load()
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	reconciliationService := service.NewUserReconciliationService(newAuthRepository(cfg, db), repository.NewUserRepository(db.DB))
+	report, err := reconciliationService.Reconcile(context.Background(), model.ReconcileOptions{DryRun: *dryRun})
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	for _, entry := range report.Entries {
+		if !*verbose && entry.Action != model.ReconcileSkip {
+			continue
+		}
+		if entry.Reason != "" {
+			fmt.Printf("%s\t%s\t%s (%s)\n", entry.Action, entry.FirebaseUID, entry.Email, entry.Reason)
+		} else {
+			fmt.Printf("%s\t%s\t%s\n", entry.Action, entry.FirebaseUID, entry.Email)
+		}
+	}
+
+	mode := "applied"
+	if report.DryRun {
+		mode = "dry run"
+	}
+	fmt.Printf("%s: %d auth users, %d rows, %d provisioned, %d emails synced, %d orphaned, %d restored, %d skipped\n",
+		mode, report.AuthUsers, report.DBUsers, report.Provisioned, report.EmailsSynced, report.Orphaned, report.Restored, report.Skipped)
+}
+
+// newAuthRepository selects the auth provider by AUTH_PROVIDER (same as the server)
+func newAuthRepository(cfg *config.Config, db *database.DB) domainrepository.AuthRepository {
+	if cfg.Auth.Provider == config.AuthProviderLocal {
+		return repository.NewLocalAuthRepository(db.DB, cfg.Auth.LocalJWTSecret, mail.NewLogMailer(), cfg.Auth.LocalActionURL)
+	}
+	firebaseAuth, err := config.InitFirebase()
+	if err != nil {
+		log.Fatalf("Failed to initialize Firebase: %v", err)
+	}
+	return repository.NewAuthRepository(firebaseAuth)
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"zerodelay/internal/config"
 	"zerodelay/internal/database"
+	domainrepository "zerodelay/internal/domain/repository"
 	"zerodelay/internal/handler"
+	"zerodelay/internal/lifecycle"
+	"zerodelay/internal/mail"
 	"zerodelay/internal/repository"
 	"zerodelay/internal/router"
 	"zerodelay/internal/service"
@@ -17,53 +23,137 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Initialize Firebase
-	firebaseAuth, err := config.InitFirebase()
-	if err != nil {
-		log.Fatalf("Failed to initialize Firebase: %v", err)
-	}
-
 	// Initialize database
 	db, err := database.NewDatabase(cfg)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	log.Println("Connected to database successfully")
 
-	// Run auto migration
-	if err := db.AutoMigrate(); err != nil {
-		log.Fatalf("Failed to run auto migration: %v", err)
+	// Apply pending migrations (migrations/*.sql)
+	if err := db.Migrate(context.Background()); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
 	}
-	log.Println("Auto migration completed successfully")
+	log.Println("Database migrations are up to date")
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.DB)
 	placeRepo := repository.NewPlaceRepository(db.DB)
-	authRepo := repository.NewAuthRepository(firebaseAuth)
+	hazardRepo := repository.NewHazardRepository(db.DB)
+	userLocationRepo := repository.NewUserLocationRepository(db.DB)
+	emailChangeRepo := repository.NewEmailChangeRepository(db.DB)
+	mailer := newMailer(cfg)
+	authRepo := newAuthRepository(cfg, db, mailer)
 
 	// Initialize services
-	userService := service.NewUserService(userRepo, authRepo)
+	emailChangeService := service.NewEmailChangeService(emailChangeRepo, userRepo, authRepo, newEmailChangeMailer(cfg, mailer), cfg.Auth.EmailChangeURL)
+	userService := service.NewUserService(userRepo, authRepo, emailChangeService)
 	placeService := service.NewPlaceService(placeRepo)
+	placeImportService := service.NewPlaceImportService(placeRepo)
+	hazardService := service.NewHazardService(hazardRepo)
+	userLocationService := service.NewUserLocationService(userLocationRepo, userRepo, hazardService, placeService)
 	authService := service.NewAuthService(authRepo, userRepo)
+	authRateLimiter := service.NewAuthRateLimiter(newAuthThrottleRepository(cfg, db))
+	reconciliationService := service.NewUserReconciliationService(authRepo, userRepo)
+
+	// Lifecycle: 登録順に起動し、SIGINT / SIGTERM を受けたら逆順に停止する
+	// （HTTP サーバー → バックグラウンドジョブ → DB の順に止まる）
+	app := lifecycle.New(cfg.Server.ShutdownTimeout)
+	app.Append(lifecycle.Hook{
+		Name: "database",
+		Stop: func(context.Context) error { return db.Close() },
+	})
+
+	// Background jobs
+	// デフォルトは無効。複数インスタンスで動かす場合は1台だけで USER_RECONCILE_INTERVAL を設定して有効にする
+	if cfg.Auth.UserReconcileInterval > 0 {
+		app.Go("user reconciliation", func(ctx context.Context) {
+			reconciliationService.RunPeriodically(ctx, cfg.Auth.UserReconcileInterval)
+		})
+		log.Printf("User reconciliation scheduled every %s", cfg.Auth.UserReconcileInterval)
+	}
 
 	// Initialize handlers
 	healthHandler := handler.NewHealthHandler()
 	userHandler := handler.NewUserHandler(userService)
+	userLocationHandler := handler.NewUserLocationHandler(userLocationService)
 	placeHandler := handler.NewPlaceHandler(placeService)
-	authHandler := handler.NewAuthHandler(authService)
+	placeImportHandler := handler.NewPlaceImportHandler(placeImportService)
+	hazardHandler := handler.NewHazardHandler(hazardService)
+	authHandler := handler.NewAuthHandler(authService, authRateLimiter, cfg.Session)
+	emailChangeHandler := handler.NewEmailChangeHandler(emailChangeService)
 
 	// Initialize Echo
 	e := echo.New()
+	// X-Forwarded-For はプライベートネットワーク上のプロキシから来た場合だけ信用する（レート制限の IP 偽装対策）
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
 
 	// Setup routes
-	router.SetupRoutes(e, healthHandler, userHandler, placeHandler, authHandler, authService)
+	router.SetupRoutes(e, healthHandler, userHandler, userLocationHandler, placeHandler, placeImportHandler, hazardHandler, authHandler, emailChangeHandler, authService, userService)
 
-	// Start server
+	// HTTP server: 停止時は新しい接続の受け付けをやめ、処理中のリクエストの完了を待つ
 	port := fmt.Sprintf(":%s", cfg.Server.Port)
-	log.Printf("Server starting on port %s", port)
-	if err := e.Start(port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	app.Append(lifecycle.Hook{
+		Name: "http server",
+		Start: func(context.Context) error {
+			log.Printf("Server starting on port %s", port)
+			go func() {
+				if err := e.Start(port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					app.Fail(fmt.Errorf("http server: %w", err))
+				}
+			}()
+			return nil
+		},
+		Stop: e.Shutdown,
+	})
+
+	if err := app.Run(context.Background()); err != nil {
+		log.Fatalf("Server stopped with error: %v", err)
+	}
+	log.Println("Server stopped")
+}
+
+// newAuthRepository selects the auth provider by AUTH_PROVIDER
+func newAuthRepository(cfg *config.Config, db *database.DB, mailer mail.Mailer) domainrepository.AuthRepository {
+	if cfg.Auth.Provider == config.AuthProviderLocal {
+		// Firebase を使わずにオフラインで動かす（ワークショップ・防災訓練・CI 向け）
+		log.Println("[INFO] Using local auth provider")
+		return repository.NewLocalAuthRepository(db.DB, cfg.Auth.LocalJWTSecret, mailer, cfg.Auth.LocalActionURL)
+	}
+
+	// Initialize Firebase
+	firebaseAuth, err := config.InitFirebase()
+	if err != nil {
+		log.Fatalf("Failed to initialize Firebase: %v", err)
+	}
+	return repository.NewAuthRepository(firebaseAuth)
+}
+
+// newAuthThrottleRepository selects where login / signup attempt counters are kept by AUTH_RATE_LIMIT_STORE
+func newAuthThrottleRepository(cfg *config.Config, db *database.DB) domainrepository.AuthThrottleRepository {
+	if cfg.Auth.RateLimitStore == config.RateLimitStorePostgres {
+		return repository.NewAuthThrottleRepository(db.DB)
+	}
+	return repository.NewMemoryAuthThrottleRepository()
+}
+
+// newMailer sends through SMTP when SMTP_HOST is set, otherwise writes emails to the log
+func newMailer(cfg *config.Config) mail.Mailer {
+	if cfg.Mail.SMTPHost == "" {
+		log.Println("[INFO] SMTP_HOST is not set; emails sent by the server are written to the log")
+		return mail.NewLogMailer()
+	}
+	return mail.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+}
+
+// newEmailChangeMailer returns the mailer for email change confirmations.
+// Firebase では確認リンクをサーバーから送るため、SMTP が未設定なら（ログに出すだけでは届かないので）無効にする。
+// nil を返すと POST /api/v1/users/me/email は 503 になる
+func newEmailChangeMailer(cfg *config.Config, mailer mail.Mailer) mail.Mailer {
+	if cfg.Auth.Provider != config.AuthProviderLocal && cfg.Mail.SMTPHost == "" {
+		log.Println("[WARN] SMTP_HOST is not set; email changes are disabled (POST /api/v1/users/me/email returns 503)")
+		return nil
 	}
+	return mailer
 }
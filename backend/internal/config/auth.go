@@ -0,0 +1,85 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+)
+
+// Auth providers selectable with AUTH_PROVIDER
+const (
+	AuthProviderFirebase = "firebase"
+	AuthProviderLocal    = "local"
+)
+
+// Rate limit stores selectable with AUTH_RATE_LIMIT_STORE
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+)
+
+// defaultUserReconcileInterval disables the users table reconciliation unless it is enabled explicitly.
+// 複数インスタンスで同時に実行しないよう、1台だけで USER_RECONCILE_INTERVAL を設定して有効にする
+const defaultUserReconcileInterval time.Duration = 0
+
+// AuthConfig holds authentication provider configuration
+type AuthConfig struct {
+	Provider string
+	// LocalJWTSecret signs tokens issued by the local provider
+	LocalJWTSecret string
+	// LocalActionURL is the base of links in verification / password reset emails of the local provider
+	LocalActionURL string
+	// EmailChangeURL is the base of the confirmation link sent to a new email address
+	EmailChangeURL string
+	// RateLimitStore is where login / signup attempt counters are kept.
+	// 複数インスタンスで動かす場合は postgres にして、インスタンス間で共有する
+	RateLimitStore string
+	// UserReconcileInterval is how often the server reconciles auth users with the users table (0 = disabled, the default)
+	UserReconcileInterval time.Duration
+}
+
+// loadAuthConfig reads AUTH_PROVIDER and LOCAL_AUTH_* environment variables
+func loadAuthConfig() AuthConfig {
+	provider := strings.ToLower(getEnv("AUTH_PROVIDER", AuthProviderFirebase))
+	if provider != AuthProviderFirebase && provider != AuthProviderLocal {
+		log.Printf("[WARN] Unknown AUTH_PROVIDER %q, using %s", provider, AuthProviderFirebase)
+		provider = AuthProviderFirebase
+	}
+
+	cfg := AuthConfig{
+		Provider:       provider,
+		LocalJWTSecret: getEnv("LOCAL_AUTH_JWT_SECRET", ""),
+		LocalActionURL: getEnv("LOCAL_AUTH_ACTION_URL", "http://localhost:8080/api/v1/auth/action"),
+		EmailChangeURL: getEnv("EMAIL_CHANGE_CONFIRM_URL", "http://localhost:8080/api/v1/auth/email-change/confirm"),
+		RateLimitStore: strings.ToLower(getEnv("AUTH_RATE_LIMIT_STORE", RateLimitStoreMemory)),
+	}
+
+	interval, err := time.ParseDuration(getEnv("USER_RECONCILE_INTERVAL", defaultUserReconcileInterval.String()))
+	if err != nil || interval < 0 {
+		log.Printf("[WARN] Invalid USER_RECONCILE_INTERVAL, using default %s: %v", defaultUserReconcileInterval, err)
+		interval = defaultUserReconcileInterval
+	}
+	cfg.UserReconcileInterval = interval
+
+	if cfg.RateLimitStore != RateLimitStoreMemory && cfg.RateLimitStore != RateLimitStorePostgres {
+		log.Printf("[WARN] Unknown AUTH_RATE_LIMIT_STORE %q, using %s", cfg.RateLimitStore, RateLimitStoreMemory)
+		cfg.RateLimitStore = RateLimitStoreMemory
+	}
+
+	if provider == AuthProviderLocal && cfg.LocalJWTSecret == "" {
+		// 未設定でも起動できるようにするが、再起動するとすべてのトークンが無効になる
+		log.Println("[WARN] LOCAL_AUTH_JWT_SECRET is not set; using a random secret (tokens are invalidated on restart)")
+		cfg.LocalJWTSecret = randomSecret()
+	}
+	return cfg
+}
+
+func randomSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to generate JWT secret: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,11 +12,18 @@ import (
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Session  SessionConfig
+	Auth     AuthConfig
+	Mail     MailConfig
 }
 
+// defaultShutdownTimeout is how long in-flight requests and background jobs get to finish on SIGTERM
+const defaultShutdownTimeout = 15 * time.Second
+
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Port string
+	Port            string
+	ShutdownTimeout time.Duration
 }
 
 // DatabaseConfig holds database connection configuration
@@ -31,13 +39,27 @@ func Load() *Config {
 	}
 
 	return &Config{
-		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-		},
+		Server: loadServerConfig(),
 		Database: DatabaseConfig{
 			URL:      getEnv("DATABASE_URL", ""),
 			LogLevel: getEnv("DB_LOG_LEVEL", "warn"), // silent, error, warn, info
 		},
+		Session: loadSessionConfig(),
+		Auth:    loadAuthConfig(),
+		Mail:    loadMailConfig(),
+	}
+}
+
+// loadServerConfig reads PORT and SHUTDOWN_TIMEOUT
+func loadServerConfig() ServerConfig {
+	timeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", defaultShutdownTimeout.String()))
+	if err != nil || timeout <= 0 {
+		log.Printf("[WARN] Invalid SHUTDOWN_TIMEOUT, using default %s: %v", defaultShutdownTimeout, err)
+		timeout = defaultShutdownTimeout
+	}
+	return ServerConfig{
+		Port:            getEnv("PORT", "8080"),
+		ShutdownTimeout: timeout,
 	}
 }
 
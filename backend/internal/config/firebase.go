@@ -15,24 +15,44 @@ import (
 
 const (
 	DefaultCredentialsFile = "serviceAccountKey.json"
+	// DefaultEmulatorProjectID is used with the Auth Emulator when FIREBASE_PROJECT_ID is not set.
+	// "demo-" で始まるプロジェクトIDはエミュレーター専用として扱われ、本番リソースにアクセスしない
+	DefaultEmulatorProjectID = "demo-zerodelay"
 
-	envCredentialsJSON     = "FIREBASE_CREDENTIALS_JSON"
+	envCredentialsJSON  = "FIREBASE_CREDENTIALS_JSON"
+	envAuthEmulatorHost = "FIREBASE_AUTH_EMULATOR_HOST"
+	envProjectID        = "FIREBASE_PROJECT_ID"
 )
 
 func InitFirebase() (*auth.Client, error) {
+	// Auth Emulator 使用時はサービスアカウント鍵なしで初期化する
+	if host := AuthEmulatorHost(); host != "" {
+		projectID := strings.TrimSpace(os.Getenv(envProjectID))
+		if projectID == "" {
+			projectID = DefaultEmulatorProjectID
+		}
+		log.Printf("[INFO] Using Firebase Auth Emulator at %s (project: %s)", host, projectID)
+		return initFirebaseWithOption(&firebase.Config{ProjectID: projectID}, option.WithoutAuthentication())
+	}
+
 	opt, err := selectCredentialOption()
 	if err != nil {
 		return nil, err
 	}
-	return initFirebaseWithOption(opt)
+	return initFirebaseWithOption(nil, opt)
 }
 
 func InitFirebaseWithCredentials(credentialsFile string) (*auth.Client, error) {
-	return initFirebaseWithOption(option.WithCredentialsFile(credentialsFile))
+	return initFirebaseWithOption(nil, option.WithCredentialsFile(credentialsFile))
+}
+
+// AuthEmulatorHost returns the host:port of the Firebase Auth Emulator, or "" when not configured
+func AuthEmulatorHost() string {
+	return strings.TrimSpace(os.Getenv(envAuthEmulatorHost))
 }
 
-func initFirebaseWithOption(opt option.ClientOption) (*auth.Client, error) {
-	app, err := firebase.NewApp(context.Background(), nil, opt)
+func initFirebaseWithOption(conf *firebase.Config, opt option.ClientOption) (*auth.Client, error) {
+	app, err := firebase.NewApp(context.Background(), conf, opt)
 	if err != nil {
 		log.Printf("[ERROR] Failed to initialize Firebase app: %v", err)
 		return nil, fmt.Errorf("failed to initialize Firebase app: %w", err)
@@ -0,0 +1,22 @@
+package config
+
+// MailConfig holds outgoing mail configuration.
+// SMTP_HOST が未設定の場合、メールは送信せずサーバーログに出力する
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
+// loadMailConfig reads SMTP_* and MAIL_FROM environment variables
+func loadMailConfig() MailConfig {
+	return MailConfig{
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		From:         getEnv("MAIL_FROM", "noreply@zerodelay.local"),
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cookie and header names shared by the auth handler and middleware
+const (
+	SessionCookieName = "session"
+	CSRFCookieName    = "csrf_token"
+	CSRFHeaderName    = "X-CSRF-Token"
+)
+
+// Firebase session cookies must live between 5 minutes and 2 weeks
+const (
+	minSessionTTL     = 5 * time.Minute
+	maxSessionTTL     = 14 * 24 * time.Hour
+	defaultSessionTTL = 5 * 24 * time.Hour
+)
+
+// SessionConfig holds cookie-based session configuration
+type SessionConfig struct {
+	TTL      time.Duration
+	Secure   bool
+	SameSite http.SameSite
+	Domain   string
+}
+
+// loadSessionConfig reads SESSION_COOKIE_* environment variables
+func loadSessionConfig() SessionConfig {
+	ttl, err := time.ParseDuration(getEnv("SESSION_COOKIE_TTL", defaultSessionTTL.String()))
+	if err != nil {
+		log.Printf("[WARN] Invalid SESSION_COOKIE_TTL, using default %s: %v", defaultSessionTTL, err)
+		ttl = defaultSessionTTL
+	}
+	if ttl < minSessionTTL {
+		ttl = minSessionTTL
+	}
+	if ttl > maxSessionTTL {
+		ttl = maxSessionTTL
+	}
+
+	sameSite := http.SameSiteLaxMode
+	switch strings.ToLower(getEnv("SESSION_COOKIE_SAMESITE", "lax")) {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		// SameSite=None はフロントエンドとバックエンドが別ドメインの場合に使用（Secure 必須）
+		sameSite = http.SameSiteNoneMode
+	}
+
+	return SessionConfig{
+		TTL:      ttl,
+		Secure:   getEnv("SESSION_COOKIE_SECURE", "true") != "false",
+		SameSite: sameSite,
+		Domain:   getEnv("SESSION_COOKIE_DOMAIN", ""),
+	}
+}
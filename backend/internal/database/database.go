@@ -9,7 +9,6 @@ import (
 	"gorm.io/gorm/logger"
 
 	"zerodelay/internal/config"
-	"zerodelay/internal/domain/model"
 )
 
 // DB holds the database connection
@@ -52,14 +51,6 @@ func NewDatabase(cfg *config.Config) (*DB, error) {
 	return &DB{db}, nil
 }
 
-// AutoMigrate runs auto migration for all models
-func (db *DB) AutoMigrate() error {
-	return db.DB.AutoMigrate(
-		&model.User{},
-		&model.Place{},
-	)
-}
-
 // Close closes the database connection
 func (db *DB) Close() error {
 	sqlDB, err := db.DB.DB()
@@ -1,15 +1,52 @@
 package model
 
+import "time"
+
 type SignUpRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+// UpgradeGuestRequest links an email and password to the caller's guest account
+type UpgradeGuestRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// ResendVerificationRequest はメール未確認のためログインできないユーザーが確認メールを再送するためのリクエスト
+type ResendVerificationRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LogoutRequest is the optional body of POST /auth/logout.
+// ログアウトは常に全端末が対象のため、AllDevices は以前のクライアントとの互換のためだけに残している
+type LogoutRequest struct {
+	AllDevices bool `json:"all_devices"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// TokenInfo は検証済みのIDトークン・セッションクッキーから取り出した情報
+type TokenInfo struct {
+	UID           string
+	Email         string
+	EmailVerified bool // トークン発行時点の値（確認直後は false のままの場合がある）
+	AuthTime      time.Time
+	Anonymous     bool // ゲスト（匿名ログイン）のトークン
+}
+
 // FirebaseAuthResponse はFirebase APIから返される内部用のレスポンス
 type FirebaseAuthResponse struct {
 	IDToken      string `json:"idToken"`
@@ -20,12 +57,23 @@ type FirebaseAuthResponse struct {
 	Registered   bool   `json:"registered,omitempty"`
 }
 
+// SecureTokenResponse はSecure Token APIから返される内部用のレスポンス
+type SecureTokenResponse struct {
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    string `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+	UserID       string `json:"user_id"`
+	ProjectID    string `json:"project_id"`
+}
+
 // AuthResponse はクライアントに返す認証レスポンス
 type AuthResponse struct {
 	IDToken      string `json:"idToken,omitempty"`
 	RefreshToken string `json:"refreshToken,omitempty"`
 	ExpiresIn    string `json:"expiresIn,omitempty"`
-	User         *User  `json:"user,omitempty"` // PostgreSQLから取得したユーザー情報
+	CSRFToken    string `json:"csrfToken,omitempty"` // セッションクッキー利用時に X-CSRF-Token ヘッダーへ設定する値
+	User         *User  `json:"user,omitempty"`      // PostgreSQLから取得したユーザー情報
 	// Firebase APIから取得した内部データ（クライアントには返さない）
 	Email      string `json:"-"`
 	LocalID    string `json:"-"`
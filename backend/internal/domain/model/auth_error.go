@@ -0,0 +1,93 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// AuthErrorCode is a stable, provider-independent error code returned to API clients
+type AuthErrorCode string
+
+const (
+	AuthErrEmailExists         AuthErrorCode = "email_already_exists"
+	AuthErrInvalidEmail        AuthErrorCode = "invalid_email"
+	AuthErrWeakPassword        AuthErrorCode = "weak_password"
+	AuthErrInvalidCredentials  AuthErrorCode = "invalid_credentials"
+	AuthErrTooManyAttempts     AuthErrorCode = "too_many_attempts"
+	AuthErrUserDisabled        AuthErrorCode = "user_disabled"
+	AuthErrUserNotFound        AuthErrorCode = "user_not_found"
+	AuthErrEmailNotFound       AuthErrorCode = "email_not_found"
+	AuthErrEmailNotVerified    AuthErrorCode = "email_not_verified"
+	AuthErrEmailVerified       AuthErrorCode = "email_already_verified"
+	AuthErrInvalidRefreshToken AuthErrorCode = "invalid_refresh_token"
+	AuthErrInvalidToken        AuthErrorCode = "invalid_token"
+	AuthErrTokenRevoked        AuthErrorCode = "token_revoked"
+	AuthErrCredentialTooOld    AuthErrorCode = "credential_too_old"
+	AuthErrInvalidActionCode   AuthErrorCode = "invalid_action_code"
+	AuthErrNotGuest            AuthErrorCode = "not_guest_account"
+	AuthErrGuestAccount        AuthErrorCode = "guest_account"
+	AuthErrMissingField        AuthErrorCode = "missing_field"
+	AuthErrFailed              AuthErrorCode = "auth_failed"
+)
+
+// AuthError is a typed authentication failure.
+// ProviderCode は Firebase などのプロバイダーが返した元のコード（ログ用）
+type AuthError struct {
+	Code         AuthErrorCode
+	ProviderCode string
+	Detail       string
+	// RetryAfter is how long the client should wait before retrying (too_many_attempts only)
+	RetryAfter time.Duration
+}
+
+func (e *AuthError) Error() string {
+	msg := string(e.Code)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.ProviderCode != "" {
+		msg += " (" + e.ProviderCode + ")"
+	}
+	return msg
+}
+
+// Is makes errors.Is match any AuthError with the same code
+func (e *AuthError) Is(target error) bool {
+	t, ok := target.(*AuthError)
+	return ok && t.Code == e.Code
+}
+
+// Sentinel errors for errors.Is checks
+var (
+	ErrAuthEmailExists         = &AuthError{Code: AuthErrEmailExists}
+	ErrAuthInvalidEmail        = &AuthError{Code: AuthErrInvalidEmail}
+	ErrAuthWeakPassword        = &AuthError{Code: AuthErrWeakPassword}
+	ErrAuthInvalidCredentials  = &AuthError{Code: AuthErrInvalidCredentials}
+	ErrAuthTooManyAttempts     = &AuthError{Code: AuthErrTooManyAttempts}
+	ErrAuthUserDisabled        = &AuthError{Code: AuthErrUserDisabled}
+	ErrAuthUserNotFound        = &AuthError{Code: AuthErrUserNotFound}
+	ErrAuthEmailNotFound       = &AuthError{Code: AuthErrEmailNotFound}
+	ErrAuthEmailNotVerified    = &AuthError{Code: AuthErrEmailNotVerified}
+	ErrAuthEmailVerified       = &AuthError{Code: AuthErrEmailVerified}
+	ErrAuthInvalidRefreshToken = &AuthError{Code: AuthErrInvalidRefreshToken}
+	ErrAuthInvalidToken        = &AuthError{Code: AuthErrInvalidToken}
+	ErrAuthTokenRevoked        = &AuthError{Code: AuthErrTokenRevoked}
+	ErrAuthCredentialTooOld    = &AuthError{Code: AuthErrCredentialTooOld}
+	ErrAuthInvalidActionCode   = &AuthError{Code: AuthErrInvalidActionCode}
+	ErrAuthNotGuest            = &AuthError{Code: AuthErrNotGuest}
+	ErrAuthGuestAccount        = &AuthError{Code: AuthErrGuestAccount}
+)
+
+// NewAuthError creates an AuthError for a provider error code
+func NewAuthError(code AuthErrorCode, providerCode string) *AuthError {
+	return &AuthError{Code: code, ProviderCode: providerCode}
+}
+
+// AsAuthError returns the AuthError in err's chain, if any
+func AsAuthError(err error) (*AuthError, bool) {
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		return authErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// AuthThrottle represents the auth_throttles table (request counters and lockout state of an
+// IP address or email on a public auth endpoint). Key は "login:ip:203.0.113.1" のような形式
+type AuthThrottle struct {
+	Key           string     `gorm:"primaryKey;type:text" json:"key"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	WindowStart   time.Time  `gorm:"not null" json:"window_start"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt *time.Time `json:"last_failure_at"`
+	LockedUntil   *time.Time `gorm:"index" json:"locked_until"`
+	UpdatedAt     time.Time  `gorm:"index" json:"updated_at"`
+}
+
+// TableName specifies the table name for AuthThrottle model
+func (AuthThrottle) TableName() string {
+	return "auth_throttles"
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// EmailChange represents the email_changes table (an email change waiting for confirmation).
+// 新しいアドレス宛の確認リンクが開かれるまで、users / Firebase のメールアドレスは変更しない
+type EmailChange struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	UserID    uint      `gorm:"not null;uniqueIndex" json:"-"`
+	User      *User     `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	NewEmail  string    `gorm:"type:text;not null" json:"pending_email"`
+	TokenHash string    `gorm:"type:text;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `json:"requested_at"`
+}
+
+// TableName specifies the table name for EmailChange model
+func (EmailChange) TableName() string {
+	return "email_changes"
+}
+
+// ChangeEmailRequest starts an email change
+type ChangeEmailRequest struct {
+	Email string `json:"email"`
+}
+
+// ConfirmEmailChangeRequest completes an email change with the token from the confirmation email
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" form:"token"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// GeoJSONMediaType is the media type of GeoJSON documents (RFC 7946)
+const GeoJSONMediaType = "application/geo+json"
+
+// FeatureCollection represents a GeoJSON FeatureCollection
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature represents a GeoJSON Feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry represents a GeoJSON geometry; Coordinates is kept raw so any geometry type can be carried
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// NewFeatureCollection creates a FeatureCollection with a non-nil feature list
+func NewFeatureCollection(features []Feature) *FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return &FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// NewPointGeometry creates a Point geometry ([lon, lat] order as required by GeoJSON)
+func NewPointGeometry(lon, lat float64) (*Geometry, error) {
+	coords, err := json.Marshal([2]float64{lon, lat})
+	if err != nil {
+		return nil, err
+	}
+	return &Geometry{Type: "Point", Coordinates: coords}, nil
+}
+
+// Value implements the driver.Valuer interface for Geometry
+func (g Geometry) Value() (driver.Value, error) {
+	b, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface for Geometry
+func (g *Geometry) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, g)
+	case string:
+		return json.Unmarshal([]byte(v), g)
+	case nil:
+		return nil
+	}
+	return errors.New("unsupported geometry value")
+}
@@ -0,0 +1,183 @@
+package model
+
+import "time"
+
+// HazardKind is the type of hazard layer a zone belongs to
+type HazardKind string
+
+const (
+	HazardKindInundationDepth    HazardKind = "inundation_depth"    // 洪水浸水想定区域（浸水深）
+	HazardKindInundationDuration HazardKind = "inundation_duration" // 浸水継続時間
+	HazardKindHouseCollapse      HazardKind = "house_collapse"      // 家屋倒壊等氾濫想定区域
+	HazardKindLandslide          HazardKind = "landslide"           // 土砂災害警戒区域
+)
+
+// IsValid reports whether the kind is one of the known values
+func (k HazardKind) IsValid() bool {
+	switch k {
+	case HazardKindInundationDepth,
+		HazardKindInundationDuration,
+		HazardKindHouseCollapse,
+		HazardKindLandslide:
+		return true
+	}
+	return false
+}
+
+// Zone types distinguishing sub-areas of house-collapse and landslide layers
+const (
+	ZoneTypeFloodFlow      = "flood_flow"      // 家屋倒壊等氾濫想定区域（氾濫流）
+	ZoneTypeBankErosion    = "bank_erosion"    // 家屋倒壊等氾濫想定区域（河岸侵食）
+	ZoneTypeWarning        = "warning"         // 土砂災害警戒区域（イエローゾーン）
+	ZoneTypeSpecialWarning = "special_warning" // 土砂災害特別警戒区域（レッドゾーン）
+)
+
+// FloodScenario is the rainfall scenario a hazard map assumes
+type FloodScenario string
+
+const (
+	FloodScenarioPlanned FloodScenario = "planned" // 計画規模降雨（L1）
+	FloodScenarioMaximum FloodScenario = "maximum" // 想定最大規模降雨（L2）
+)
+
+// IsValid reports whether the scenario is one of the known values
+func (s FloodScenario) IsValid() bool {
+	return s == FloodScenarioPlanned || s == FloodScenarioMaximum
+}
+
+// depthRankLabels follows the MLIT inundation-depth legend (浸水深ランク)
+var depthRankLabels = map[int]string{
+	1: "0.5m未満",
+	2: "0.5m以上3.0m未満",
+	3: "3.0m以上5.0m未満",
+	4: "5.0m以上10.0m未満",
+	5: "10.0m以上20.0m未満",
+	6: "20.0m以上",
+}
+
+// MaxDepthRank is the deepest inundation-depth rank
+const MaxDepthRank = 6
+
+// DepthRankLabel returns the depth range of an inundation-depth rank
+func DepthRankLabel(rank int) string {
+	return depthRankLabels[rank]
+}
+
+// durationRankLabels follows the MLIT inundation-duration legend (浸水継続時間)
+var durationRankLabels = map[int]string{
+	1: "12時間未満",
+	2: "12時間以上1日未満",
+	3: "1日以上3日未満",
+	4: "3日以上1週間未満",
+	5: "1週間以上2週間未満",
+	6: "2週間以上4週間未満",
+	7: "4週間以上",
+}
+
+// MaxDurationRank is the longest inundation-duration rank
+const MaxDurationRank = 7
+
+// DurationRankLabel returns the duration range of an inundation-duration rank
+func DurationRankLabel(rank int) string {
+	return durationRankLabels[rank]
+}
+
+// HazardZone represents the hazard_zones table (one polygon of a hazard map layer)
+type HazardZone struct {
+	ID           uint          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Kind         HazardKind    `gorm:"type:text;not null;index" json:"kind"`
+	Source       string        `gorm:"type:text;not null;index" json:"source"` // 取込元データセットの識別子（再取込時の置換単位）
+	SourceRiver  string        `gorm:"type:text" json:"source_river"`
+	Scenario     FloodScenario `gorm:"type:text;index" json:"scenario"`
+	DepthRank    int           `gorm:"type:integer;not null;default:0" json:"depth_rank"`
+	DurationRank int           `gorm:"type:integer;not null;default:0" json:"duration_rank"`
+	ZoneType     string        `gorm:"type:text" json:"zone_type,omitempty"`
+	Geometry     *Geometry     `gorm:"type:jsonb;not null" json:"geometry,omitempty"`
+
+	// 点検索の絞り込み用の外接矩形
+	MinLon float64 `gorm:"type:double precision;not null;index:idx_hazard_zones_bbox,priority:3" json:"-"`
+	MinLat float64 `gorm:"type:double precision;not null;index:idx_hazard_zones_bbox,priority:1" json:"-"`
+	MaxLon float64 `gorm:"type:double precision;not null;index:idx_hazard_zones_bbox,priority:4" json:"-"`
+	MaxLat float64 `gorm:"type:double precision;not null;index:idx_hazard_zones_bbox,priority:2" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for HazardZone model
+func (HazardZone) TableName() string {
+	return "hazard_zones"
+}
+
+// HazardImportOptions controls a GeoJSON hazard-layer import.
+// Empty fields are read from each feature's properties.
+type HazardImportOptions struct {
+	Source      string
+	Kind        HazardKind
+	Scenario    FloodScenario
+	SourceRiver string
+	DryRun      bool
+	// AllowEmpty replaces the source's zones even when no feature could be imported (= 取込元のゾーンを削除する)
+	AllowEmpty bool
+	// AllowSkips writes the import even when more than MaxHazardSkipRatio of the features were skipped
+	AllowSkips bool
+}
+
+// MaxHazardSkipRatio is the share of skipped features above which an import is refused unless AllowSkips is set.
+// -kind の指定違いやプロパティ名の違いで既存のゾーンを失わないようにする
+const MaxHazardSkipRatio = 0.1
+
+// HazardImportSkip records a feature that could not be imported
+type HazardImportSkip struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// HazardImportReport summarizes a hazard-layer import
+type HazardImportReport struct {
+	Source   string             `json:"source"`
+	DryRun   bool               `json:"dry_run"`
+	Total    int                `json:"total"`
+	Imported int                `json:"imported"`
+	Replaced int64              `json:"replaced"` // 置き換えで削除された既存ゾーン数
+	Skipped  []HazardImportSkip `json:"skipped"`
+}
+
+// HazardMatch is a hazard zone that applies at the assessed point
+type HazardMatch struct {
+	Kind          HazardKind    `json:"kind"`
+	Source        string        `json:"source"`
+	SourceRiver   string        `json:"source_river,omitempty"`
+	Scenario      FloodScenario `json:"scenario,omitempty"`
+	DepthRank     int           `json:"depth_rank,omitempty"`
+	DepthLabel    string        `json:"depth_label,omitempty"`
+	DurationRank  int           `json:"duration_rank,omitempty"`
+	DurationLabel string        `json:"duration_label,omitempty"`
+	ZoneType      string        `json:"zone_type,omitempty"`
+}
+
+// HazardSummary aggregates the matches (worst case across scenarios)
+type HazardSummary struct {
+	MaxDepthRank                 int    `json:"max_depth_rank"`
+	MaxDepthLabel                string `json:"max_depth_label,omitempty"`
+	MaxDurationRank              int    `json:"max_duration_rank"`
+	MaxDurationLabel             string `json:"max_duration_label,omitempty"`
+	HouseCollapseZone            bool   `json:"house_collapse_zone"`
+	LandslideWarningArea         bool   `json:"landslide_warning_area"`
+	LandslideSpecialWarningArea  bool   `json:"landslide_special_warning_area"`
+	VerticalEvacuationSufficient bool   `json:"vertical_evacuation_sufficient"`
+}
+
+// RecommendedAction is a human-readable evacuation recommendation
+type RecommendedAction struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// HazardAssessment is the risk assessment at a coordinate
+type HazardAssessment struct {
+	Lat                float64             `json:"lat"`
+	Lon                float64             `json:"lon"`
+	Hazards            []HazardMatch       `json:"hazards"`
+	Summary            HazardSummary       `json:"summary"`
+	RecommendedActions []RecommendedAction `json:"recommended_actions"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// AuthUser is the provider-independent view of an account in the auth provider
+type AuthUser struct {
+	UID           string
+	Email         string
+	EmailVerified bool
+	Disabled      bool
+	Anonymous     bool
+	CreatedAt     time.Time
+}
+
+// LocalAuthUser represents the local_auth_users table used by the local auth provider
+// (AUTH_PROVIDER=local). Firebase を使わない場合の認証情報を保持する
+type LocalAuthUser struct {
+	UID              string    `gorm:"type:text;primaryKey"`
+	Email            string    `gorm:"type:text;not null;uniqueIndex:idx_local_auth_users_email_registered,where:email <> ''"` // ゲストは空文字
+	PasswordHash     string    `gorm:"type:text;not null"`
+	EmailVerified    bool      `gorm:"not null;default:false"`
+	Disabled         bool      `gorm:"not null;default:false"`
+	Anonymous        bool      `gorm:"not null;default:false"`
+	TokensValidAfter time.Time `gorm:"not null"` // これより前に発行されたトークンは失効扱い
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// TableName specifies the table name for LocalAuthUser model
+func (LocalAuthUser) TableName() string {
+	return "local_auth_users"
+}
+
+// ActionCodeRequest carries the one-time code from a verification or password reset email
+type ActionCodeRequest struct {
+	OobCode string `json:"oobCode"`
+}
+
+// ConfirmPasswordResetRequest sets a new password with the code from a password reset email
+type ConfirmPasswordResetRequest struct {
+	OobCode     string `json:"oobCode"`
+	NewPassword string `json:"newPassword"`
+}
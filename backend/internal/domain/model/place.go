@@ -1,18 +1,217 @@
 package model
 
+import "time"
+
 // Place represents the place table
 type Place struct {
-	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name     string `gorm:"type:text" json:"name"`
-	NameKana string `gorm:"type:text;column:name_kana" json:"name_kana"`
-	Address  string `gorm:"type:text" json:"address"`
-	Lat      string `gorm:"type:text" json:"lat"`
-	Lon      string `gorm:"type:text" json:"lon"`
-	URL      string `gorm:"type:text;column:url" json:"url"`
-	Tel      string `gorm:"type:text" json:"tel"`
+	ID       uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name     string  `gorm:"type:text" json:"name"`
+	NameKana string  `gorm:"type:text;column:name_kana" json:"name_kana"`
+	Address  string  `gorm:"type:text" json:"address"`
+	Lat      float64 `gorm:"type:double precision;not null;index:idx_place_lat_lon,priority:1" json:"lat"`
+	Lon      float64 `gorm:"type:double precision;not null;index:idx_place_lat_lon,priority:2" json:"lon"`
+	URL      string  `gorm:"type:text;column:url" json:"url"`
+	Tel      string  `gorm:"type:text" json:"tel"`
+
+	// オープンデータ取込時の安定ID（全国地方公共団体コード-ID など）
+	ExternalID *string `gorm:"type:text;uniqueIndex" json:"external_id,omitempty"`
+
+	Category PlaceCategory       `gorm:"type:text;not null;default:other;index" json:"category"`
+	SafeFor  DisasterSuitability `gorm:"embedded;embeddedPrefix:safe_for_" json:"safe_for"`
+
+	// 収容状況（専用エンドポイントでのみ更新）
+	Capacity        int         `gorm:"type:integer;not null;default:0" json:"capacity"` // 0 は未設定
+	Occupancy       int         `gorm:"type:integer;not null;default:0" json:"occupancy"`
+	Status          PlaceStatus `gorm:"type:text;not null;default:closed;index" json:"status"`
+	StatusUpdatedAt *time.Time  `json:"status_updated_at"`
 }
 
 // TableName specifies the table name for Place model
 func (Place) TableName() string {
 	return "place"
 }
+
+// PlaceCategory is the designation of an evacuation place
+type PlaceCategory string
+
+const (
+	PlaceCategoryEmergencyEvacuationSite PlaceCategory = "emergency_evacuation_site" // 指定緊急避難場所
+	PlaceCategoryDesignatedShelter       PlaceCategory = "designated_shelter"        // 指定避難所
+	PlaceCategoryWelfareShelter          PlaceCategory = "welfare_shelter"           // 福祉避難所
+	PlaceCategoryEvacuationBuilding      PlaceCategory = "evacuation_building"       // 避難ビル（津波避難ビル等）
+	PlaceCategoryTemporaryAssembly       PlaceCategory = "temporary_assembly"        // 一時避難場所
+	PlaceCategoryOther                   PlaceCategory = "other"
+)
+
+// IsValid reports whether the category is one of the known values
+func (c PlaceCategory) IsValid() bool {
+	switch c {
+	case PlaceCategoryEmergencyEvacuationSite,
+		PlaceCategoryDesignatedShelter,
+		PlaceCategoryWelfareShelter,
+		PlaceCategoryEvacuationBuilding,
+		PlaceCategoryTemporaryAssembly,
+		PlaceCategoryOther:
+		return true
+	}
+	return false
+}
+
+// DisasterType is a kind of disaster a place can be certified for (災害種別)
+type DisasterType string
+
+const (
+	DisasterFlood       DisasterType = "flood"        // 洪水
+	DisasterLandslide   DisasterType = "landslide"    // 崖崩れ、土石流及び地滑り
+	DisasterStormSurge  DisasterType = "storm_surge"  // 高潮
+	DisasterEarthquake  DisasterType = "earthquake"   // 地震
+	DisasterTsunami     DisasterType = "tsunami"      // 津波
+	DisasterLargeFire   DisasterType = "large_fire"   // 大規模な火事
+	DisasterInlandWater DisasterType = "inland_water" // 内水氾濫
+	DisasterVolcano     DisasterType = "volcano"      // 火山現象
+)
+
+// DisasterTypes lists every supported disaster type
+var DisasterTypes = []DisasterType{
+	DisasterFlood,
+	DisasterLandslide,
+	DisasterStormSurge,
+	DisasterEarthquake,
+	DisasterTsunami,
+	DisasterLargeFire,
+	DisasterInlandWater,
+	DisasterVolcano,
+}
+
+// IsValid reports whether the disaster type is one of the known values
+func (t DisasterType) IsValid() bool {
+	for _, v := range DisasterTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// DisasterSuitability records which disaster types a place is certified for
+type DisasterSuitability struct {
+	Flood       bool `gorm:"not null;default:false" json:"flood"`
+	Landslide   bool `gorm:"not null;default:false" json:"landslide"`
+	StormSurge  bool `gorm:"not null;default:false" json:"storm_surge"`
+	Earthquake  bool `gorm:"not null;default:false" json:"earthquake"`
+	Tsunami     bool `gorm:"not null;default:false" json:"tsunami"`
+	LargeFire   bool `gorm:"not null;default:false" json:"large_fire"`
+	InlandWater bool `gorm:"not null;default:false" json:"inland_water"`
+	Volcano     bool `gorm:"not null;default:false" json:"volcano"`
+}
+
+// Supports reports whether the place is certified for the given disaster type
+func (d DisasterSuitability) Supports(t DisasterType) bool {
+	if f := d.field(t); f != nil {
+		return *f
+	}
+	return false
+}
+
+// Set marks the place as certified (or not) for the given disaster type
+func (d *DisasterSuitability) Set(t DisasterType, safe bool) {
+	if f := d.field(t); f != nil {
+		*f = safe
+	}
+}
+
+func (d *DisasterSuitability) field(t DisasterType) *bool {
+	switch t {
+	case DisasterFlood:
+		return &d.Flood
+	case DisasterLandslide:
+		return &d.Landslide
+	case DisasterStormSurge:
+		return &d.StormSurge
+	case DisasterEarthquake:
+		return &d.Earthquake
+	case DisasterTsunami:
+		return &d.Tsunami
+	case DisasterLargeFire:
+		return &d.LargeFire
+	case DisasterInlandWater:
+		return &d.InlandWater
+	case DisasterVolcano:
+		return &d.Volcano
+	}
+	return nil
+}
+
+// PlaceStatus is the operating state of a shelter
+type PlaceStatus string
+
+const (
+	PlaceStatusOpen   PlaceStatus = "open"
+	PlaceStatusClosed PlaceStatus = "closed"
+	PlaceStatusFull   PlaceStatus = "full"
+)
+
+// IsValid reports whether the status is one of the known values
+func (s PlaceStatus) IsValid() bool {
+	switch s {
+	case PlaceStatusOpen, PlaceStatusClosed, PlaceStatusFull:
+		return true
+	}
+	return false
+}
+
+// PlaceOccupancyLog represents the place_occupancy_history table (time series of status changes)
+type PlaceOccupancyLog struct {
+	ID         uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlaceID    uint        `gorm:"not null;index:idx_place_occupancy_history_place_time,priority:1" json:"place_id"`
+	Status     PlaceStatus `gorm:"type:text;not null" json:"status"`
+	Capacity   int         `gorm:"type:integer;not null" json:"capacity"`
+	Occupancy  int         `gorm:"type:integer;not null" json:"occupancy"`
+	UpdatedBy  string      `gorm:"type:text" json:"updated_by"` // 更新者のFirebase UID
+	RecordedAt time.Time   `gorm:"not null;index:idx_place_occupancy_history_place_time,priority:2" json:"recorded_at"`
+}
+
+// TableName specifies the table name for PlaceOccupancyLog model
+func (PlaceOccupancyLog) TableName() string {
+	return "place_occupancy_history"
+}
+
+// UpdateOccupancyRequest represents a partial update of a shelter's live status
+type UpdateOccupancyRequest struct {
+	Status    *PlaceStatus `json:"status,omitempty"`
+	Capacity  *int         `json:"capacity,omitempty"`
+	Occupancy *int         `json:"occupancy,omitempty"`
+}
+
+// PlaceWithDistance is a place annotated with its distance from a query point
+type PlaceWithDistance struct {
+	Place
+	DistanceMeters float64 `gorm:"column:distance_meters" json:"distance_meters"`
+}
+
+// NearbyQuery represents a nearest-place search around a coordinate
+type NearbyQuery struct {
+	Lat          float64
+	Lon          float64
+	RadiusMeters float64
+	Limit        int
+	Filter       PlaceFilter
+}
+
+// BoundingBox represents a lon/lat rectangle (minLon,minLat,maxLon,maxLat).
+// MinLon > MaxLon means the rectangle crosses the antimeridian (±180°)
+type BoundingBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// PlaceFilter holds optional conditions for listing places
+type PlaceFilter struct {
+	BBox          *BoundingBox
+	Categories    []PlaceCategory // いずれかに一致
+	DisasterTypes []DisasterType  // すべてに対応している場所のみ
+	Statuses      []PlaceStatus   // いずれかに一致
+	HasVacancy    bool            // 開設中かつ空きがある場所のみ
+}
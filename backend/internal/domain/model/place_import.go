@@ -0,0 +1,35 @@
+package model
+
+// PlaceImportAction is the outcome of importing a single CSV row
+type PlaceImportAction string
+
+const (
+	PlaceImportInsert PlaceImportAction = "insert"
+	PlaceImportUpdate PlaceImportAction = "update"
+	PlaceImportSkip   PlaceImportAction = "skip"
+)
+
+// PlaceImportOptions controls a bulk place import
+type PlaceImportOptions struct {
+	DryRun   bool
+	Category PlaceCategory // 取込データの施設区分（未指定時は指定緊急避難場所）
+}
+
+// PlaceImportRow is the result for one CSV row
+type PlaceImportRow struct {
+	Line       int               `json:"line"`
+	ExternalID string            `json:"external_id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Action     PlaceImportAction `json:"action"`
+	Reason     string            `json:"reason,omitempty"`
+}
+
+// PlaceImportReport summarizes a bulk place import
+type PlaceImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`
+	Inserted int              `json:"inserted"`
+	Updated  int              `json:"updated"`
+	Skipped  int              `json:"skipped"`
+	Rows     []PlaceImportRow `json:"rows"`
+}
@@ -3,18 +3,42 @@ package model
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 )
 
+// UserRole is the authorization role of a user
+type UserRole string
+
+const (
+	RoleResident       UserRole = "resident"        // 住民（自分自身のデータのみ操作可能）
+	RoleShelterStaff   UserRole = "shelter_staff"   // 避難所職員（収容状況を更新可能）
+	RoleMunicipalAdmin UserRole = "municipal_admin" // 自治体管理者（全データを管理可能）
+)
+
+// IsValid reports whether the role is one of the known values
+func (r UserRole) IsValid() bool {
+	switch r {
+	case RoleResident, RoleShelterStaff, RoleMunicipalAdmin:
+		return true
+	}
+	return false
+}
+
 // User represents the users table
 type User struct {
-	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
-	FirebaseUID string `gorm:"type:text;uniqueIndex;not null" json:"firebase_uid"`
-	Email       string `gorm:"type:text;uniqueIndex;not null" json:"email"`
-	Name        string `gorm:"type:text" json:"name"`
-	NameKana    string `gorm:"type:text;column:name_kana" json:"name_kana"`
-	Old         int    `gorm:"type:integer" json:"old"`
-	Sex         string `gorm:"type:text" json:"sex"`
-	Setting     JSON   `gorm:"type:json" json:"setting"`
+	ID          uint     `gorm:"primaryKey;autoIncrement" json:"id"`
+	FirebaseUID string   `gorm:"type:text;uniqueIndex;not null" json:"firebase_uid"`
+	Email       string   `gorm:"type:text;not null;uniqueIndex:idx_users_email_registered,where:email <> ''" json:"email"` // ゲストは空文字
+	Name        string   `gorm:"type:text" json:"name"`
+	NameKana    string   `gorm:"type:text;column:name_kana" json:"name_kana"`
+	Old         int      `gorm:"type:integer" json:"old"`
+	Sex         string   `gorm:"type:text" json:"sex"`
+	Setting     JSON     `gorm:"type:json" json:"setting"`
+	Role        UserRole `gorm:"type:text;not null;default:resident" json:"role"`
+	// IsGuest marks a user signed in anonymously; cleared when the guest links an email and password
+	IsGuest bool `gorm:"not null;default:false" json:"is_guest"`
+	// OrphanedAt is set by the reconciliation job when the auth provider no longer has this user
+	OrphanedAt *time.Time `gorm:"index" json:"orphaned_at,omitempty"`
 }
 
 // TableName specifies the table name for User model
@@ -32,6 +56,11 @@ type UpdateProfileRequest struct {
 	Setting  JSON    `json:"setting,omitempty"`
 }
 
+// UpdateRoleRequest represents a role change by an administrator
+type UpdateRoleRequest struct {
+	Role UserRole `json:"role"`
+}
+
 // JSON is a custom type for handling JSON fields in GORM
 type JSON map[string]interface{}
 
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// LocationKind is the purpose of a saved location
+type LocationKind string
+
+const (
+	LocationKindHome   LocationKind = "home"   // 自宅
+	LocationKindWork   LocationKind = "work"   // 勤務先
+	LocationKindSchool LocationKind = "school" // 学校
+	LocationKindFamily LocationKind = "family" // 実家・家族の家
+	LocationKindOther  LocationKind = "other"
+)
+
+// IsValid reports whether the kind is one of the known values
+func (k LocationKind) IsValid() bool {
+	switch k {
+	case LocationKindHome, LocationKindWork, LocationKindSchool, LocationKindFamily, LocationKindOther:
+		return true
+	}
+	return false
+}
+
+// UserLocation represents the user_locations table (named places registered by a user)
+type UserLocation struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint         `gorm:"not null;index" json:"user_id"`
+	User      *User        `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Label     string       `gorm:"type:text;not null" json:"label"`
+	Kind      LocationKind `gorm:"type:text;not null;default:other" json:"kind"`
+	Address   string       `gorm:"type:text" json:"address"`
+	Lat       float64      `gorm:"type:double precision;not null" json:"lat"`
+	Lon       float64      `gorm:"type:double precision;not null" json:"lon"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TableName specifies the table name for UserLocation model
+func (UserLocation) TableName() string {
+	return "user_locations"
+}
+
+// UserLocationRequest represents a create or partial update of a saved location
+type UserLocationRequest struct {
+	Label   *string       `json:"label,omitempty"`
+	Kind    *LocationKind `json:"kind,omitempty"`
+	Address *string       `json:"address,omitempty"`
+	Lat     *float64      `json:"lat,omitempty"`
+	Lon     *float64      `json:"lon,omitempty"`
+}
+
+// LocationRisk is the computed hazard summary of a saved location
+type LocationRisk struct {
+	Summary            HazardSummary       `json:"summary"`
+	RecommendedActions []RecommendedAction `json:"recommended_actions"`
+}
+
+// UserLocationWithRisk is a saved location annotated with its risk and nearby suitable shelters
+type UserLocationWithRisk struct {
+	UserLocation
+	Risk            *LocationRisk       `json:"risk"`
+	NearestShelters []PlaceWithDistance `json:"nearest_shelters"`
+}
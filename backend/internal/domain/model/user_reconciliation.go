@@ -0,0 +1,38 @@
+package model
+
+// ReconcileAction is what the reconciliation did (or would do) for one user
+type ReconcileAction string
+
+const (
+	ReconcileProvision ReconcileAction = "provision"  // 認証プロバイダーにだけ存在 → users に行を作成
+	ReconcileSyncEmail ReconcileAction = "sync_email" // アプリ外で変更されたメールアドレスを反映
+	ReconcileOrphan    ReconcileAction = "orphan"     // users にだけ存在 → orphaned_at を設定
+	ReconcileRestore   ReconcileAction = "restore"    // 孤立フラグが立っていたが認証ユーザーが存在する
+	ReconcileSkip      ReconcileAction = "skip"       // 自動では解消できない（要確認）
+)
+
+// ReconcileOptions controls a reconciliation run
+type ReconcileOptions struct {
+	DryRun bool
+}
+
+// ReconcileEntry is the result for one user
+type ReconcileEntry struct {
+	FirebaseUID string          `json:"firebase_uid"`
+	Email       string          `json:"email,omitempty"`
+	Action      ReconcileAction `json:"action"`
+	Reason      string          `json:"reason,omitempty"`
+}
+
+// ReconcileReport summarizes a reconciliation of the auth provider's users and the users table
+type ReconcileReport struct {
+	DryRun       bool             `json:"dry_run"`
+	AuthUsers    int              `json:"auth_users"`
+	DBUsers      int              `json:"db_users"`
+	Provisioned  int              `json:"provisioned"`
+	EmailsSynced int              `json:"emails_synced"`
+	Orphaned     int              `json:"orphaned"`
+	Restored     int              `json:"restored"`
+	Skipped      int              `json:"skipped"`
+	Entries      []ReconcileEntry `json:"entries"`
+}
@@ -2,18 +2,34 @@ package repository
 
 import (
 	"context"
-
-	fbauth "firebase.google.com/go/v4/auth"
+	"time"
 
 	"zerodelay/internal/domain/model"
 )
 
+// AuthRepository abstracts the authentication provider (Firebase or the local provider)
 type AuthRepository interface {
 	SignUp(ctx context.Context, req *model.SignUpRequest) (*model.AuthResponse, error)
+	// SignUpAnonymously creates a guest user with no email or password
+	SignUpAnonymously(ctx context.Context) (*model.AuthResponse, error)
+	// LinkEmailPassword upgrades the anonymous user of the ID token to an email/password user without changing its UID
+	LinkEmailPassword(ctx context.Context, idToken string, email string, password string) (*model.AuthResponse, error)
 	Login(ctx context.Context, req *model.LoginRequest) (*model.AuthResponse, error)
-	VerifyIDToken(ctx context.Context, idToken string) (string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*model.AuthResponse, error)
+	VerifyIDToken(ctx context.Context, idToken string) (*model.TokenInfo, error)
+	CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error)
+	VerifySessionCookie(ctx context.Context, sessionCookie string) (*model.TokenInfo, error)
+	VerifyIDTokenAndCheckRevoked(ctx context.Context, idToken string) (*model.TokenInfo, error)
+	VerifySessionCookieAndCheckRevoked(ctx context.Context, sessionCookie string) (*model.TokenInfo, error)
+	RevokeRefreshTokens(ctx context.Context, uid string) error
+	// UpdateEmail sets a new email and marks it verified; callers must have confirmed ownership of the address
 	UpdateEmail(ctx context.Context, uid string, newEmail string) error
 	DeleteUser(ctx context.Context, uid string) error
 	SendEmailVerification(ctx context.Context, idToken string) error
-	GetUser(ctx context.Context, uid string) (*fbauth.UserRecord, error)
+	SendPasswordResetEmail(ctx context.Context, email string) error
+	ApplyEmailVerification(ctx context.Context, oobCode string) error
+	ConfirmPasswordReset(ctx context.Context, oobCode string, newPassword string) error
+	GetUser(ctx context.Context, uid string) (*model.AuthUser, error)
+	// ListUsers returns every user of the provider (used by the users table reconciliation)
+	ListUsers(ctx context.Context) ([]model.AuthUser, error)
 }
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// AuthThrottleRepository stores rate limit counters and lockouts of the public auth endpoints.
+// 単一インスタンスではメモリ、複数インスタンスでは PostgreSQL の実装を使う
+type AuthThrottleRepository interface {
+	// Hit counts a request in the fixed window of the key and returns the count and when the window started
+	Hit(ctx context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error)
+	// RecordFailure counts a consecutive failure. Failures older than resetAfter are forgotten.
+	RecordFailure(ctx context.Context, key string, resetAfter time.Duration, now time.Time) (int, error)
+	// Lock blocks the key until the given time
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns the end of the key's lockout, or the zero time when it is not locked
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error)
+	// ResetFailures clears the consecutive failures and lockout of the key
+	ResetFailures(ctx context.Context, key string) error
+	// DeleteStale removes entries not updated since before and not locked
+	DeleteStale(ctx context.Context, before time.Time, now time.Time) error
+}
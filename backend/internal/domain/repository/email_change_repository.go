@@ -0,0 +1,15 @@
+package repository
+
+import "zerodelay/internal/domain/model"
+
+// EmailChangeRepository defines the interface for pending email change operations
+type EmailChangeRepository interface {
+	// Replace stores the change, discarding any earlier pending change of the same user
+	Replace(change *model.EmailChange) error
+	FindByTokenHash(tokenHash string) (*model.EmailChange, error)
+	FindByUserID(userID uint) (*model.EmailChange, error)
+	DeleteByUserID(userID uint) error
+	// Apply sets the user's email, deletes the pending change and runs fn before committing;
+	// an error from fn rolls everything back
+	Apply(change *model.EmailChange, fn func() error) error
+}
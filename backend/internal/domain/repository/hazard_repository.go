@@ -0,0 +1,10 @@
+package repository
+
+import "zerodelay/internal/domain/model"
+
+// HazardRepository defines the interface for hazard zone data operations
+type HazardRepository interface {
+	ReplaceBySource(source string, zones []*model.HazardZone) (int64, error)
+	CountBySource(source string) (int64, error)
+	FindByBBoxContaining(lat, lon float64) ([]model.HazardZone, error)
+}
@@ -7,6 +7,14 @@ type PlaceRepository interface {
 	Create(place *model.Place) error
 	FindByID(id uint) (*model.Place, error)
 	FindAll() ([]model.Place, error)
+	FindByFilter(filter model.PlaceFilter) ([]model.Place, error)
+	FindByExternalIDs(externalIDs []string) ([]model.Place, error)
+	FindNearby(query model.NearbyQuery) ([]model.PlaceWithDistance, error)
 	Update(place *model.Place) error
+	// UpdateOccupancy locks the place row, lets apply change its occupancy fields and saves them
+	// together with the history entry apply returns, in one transaction (gorm.ErrRecordNotFound if missing)
+	UpdateOccupancy(id uint, apply func(place *model.Place) (*model.PlaceOccupancyLog, error)) (*model.Place, error)
+	FindOccupancyHistory(placeID uint, limit int) ([]model.PlaceOccupancyLog, error)
 	Delete(id uint) error
+	SaveImported(creates []*model.Place, updates []*model.Place) error
 }
@@ -0,0 +1,13 @@
+package repository
+
+import "zerodelay/internal/domain/model"
+
+// UserLocationRepository defines the interface for saved location data operations
+type UserLocationRepository interface {
+	Create(location *model.UserLocation) error
+	FindByID(id uint) (*model.UserLocation, error)
+	FindByUserID(userID uint) ([]model.UserLocation, error)
+	CountByUserID(userID uint) (int64, error)
+	Update(location *model.UserLocation) error
+	Delete(id uint) error
+}
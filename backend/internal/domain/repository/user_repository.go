@@ -10,5 +10,9 @@ type UserRepository interface {
 	FindByEmail(email string) (*model.User, error)
 	FindAll() ([]model.User, error)
 	Update(user *model.User) error
+	// UpdateColumns updates only the given columns so that concurrent edits of other columns are kept
+	UpdateColumns(id uint, columns map[string]interface{}) error
 	Delete(id uint) error
+	// DeleteInTransaction deletes the user and runs fn before committing; an error from fn rolls the deletion back
+	DeleteInTransaction(id uint, fn func() error) error
 }
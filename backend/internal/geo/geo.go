@@ -0,0 +1,60 @@
+package geo
+
+import "math"
+
+// EarthRadiusMeters is the mean Earth radius used for distance calculations
+const EarthRadiusMeters = 6371008.8
+
+// ValidCoordinate reports whether lat/lon are within WGS84 bounds
+func ValidCoordinate(lat, lon float64) bool {
+	if math.IsNaN(lat) || math.IsNaN(lon) {
+		return false
+	}
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// Distance returns the great-circle distance in meters between two points (haversine)
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Around returns a lat/lon rectangle that contains every point within radiusMeters of the center.
+// It is used as an index-friendly prefilter before the exact distance check.
+// When the rectangle crosses the antimeridian (±180°), minLon is greater than maxLon.
+func Around(lat, lon, radiusMeters float64) (minLat, minLon, maxLat, maxLon float64) {
+	// Distance と同じ球面上の角距離（ラジアン）で計算する
+	angular := radiusMeters / EarthRadiusMeters
+	dLat := angular * 180 / math.Pi
+	minLat = lat - dLat
+	maxLat = lat + dLat
+
+	// 極を含む場合は全経度を対象にする
+	if minLat <= -90 || maxLat >= 90 {
+		return math.Max(-90, minLat), -180, math.Min(90, maxLat), 180
+	}
+
+	// 円に接する経線までの経度差: asin(sin(r/R) / cos(lat))
+	ratio := math.Sin(angular) / math.Cos(lat*math.Pi/180)
+	if ratio >= 1 {
+		return minLat, -180, maxLat, 180
+	}
+	dLon := math.Asin(ratio) * 180 / math.Pi
+	minLon = lon - dLon
+	maxLon = lon + dLon
+
+	// ±180° をまたぐ場合は反対側に折り返す（minLon > maxLon になる）
+	if minLon < -180 {
+		minLon += 360
+	}
+	if maxLon > 180 {
+		maxLon -= 360
+	}
+	return minLat, minLon, maxLat, maxLon
+}
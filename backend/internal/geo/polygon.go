@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnsupportedGeometry is returned for GeoJSON geometries that are not (Multi)Polygons
+var ErrUnsupportedGeometry = errors.New("unsupported geometry type")
+
+// Ring is a closed linear ring of [lon, lat] positions
+type Ring [][2]float64
+
+// Polygon is an outer ring followed by zero or more holes
+type Polygon []Ring
+
+// MultiPolygon is a set of polygons
+type MultiPolygon []Polygon
+
+// ParsePolygons decodes the coordinates of a GeoJSON Polygon or MultiPolygon
+func ParsePolygons(geometryType string, coordinates json.RawMessage) (MultiPolygon, error) {
+	switch geometryType {
+	case "Polygon":
+		var p Polygon
+		if err := json.Unmarshal(coordinates, &p); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		if len(p) == 0 {
+			return nil, errors.New("empty Polygon")
+		}
+		return MultiPolygon{p}, nil
+	case "MultiPolygon":
+		var mp MultiPolygon
+		if err := json.Unmarshal(coordinates, &mp); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+		if len(mp) == 0 {
+			return nil, errors.New("empty MultiPolygon")
+		}
+		return mp, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedGeometry, geometryType)
+}
+
+// Bounds returns the bounding rectangle of all outer rings
+func (mp MultiPolygon) Bounds() (minLon, minLat, maxLon, maxLat float64) {
+	minLon, minLat = math.Inf(1), math.Inf(1)
+	maxLon, maxLat = math.Inf(-1), math.Inf(-1)
+	for _, p := range mp {
+		if len(p) == 0 {
+			continue
+		}
+		for _, pt := range p[0] {
+			minLon = math.Min(minLon, pt[0])
+			maxLon = math.Max(maxLon, pt[0])
+			minLat = math.Min(minLat, pt[1])
+			maxLat = math.Max(maxLat, pt[1])
+		}
+	}
+	return minLon, minLat, maxLon, maxLat
+}
+
+// Contains reports whether the point lies inside any of the polygons
+func (mp MultiPolygon) Contains(lon, lat float64) bool {
+	for _, p := range mp {
+		if p.Contains(lon, lat) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether the point lies inside the outer ring and outside every hole
+func (p Polygon) Contains(lon, lat float64) bool {
+	if len(p) == 0 || !p[0].contains(lon, lat) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.contains(lon, lat) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains is the even-odd ray casting test
+func (r Ring) contains(lon, lat float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/service"
+)
+
+// authErrorResponse is the body returned for authentication failures.
+// code はクライアントが分岐に使う安定した値、messages は画面表示用（ja / en）
+type authErrorResponse struct {
+	Code     model.AuthErrorCode `json:"code"`
+	Error    string              `json:"error"`
+	Messages authErrorMessages   `json:"messages"`
+}
+
+type authErrorMessages struct {
+	JA string `json:"ja"`
+	EN string `json:"en"`
+}
+
+type authErrorSpec struct {
+	status   int
+	messages authErrorMessages
+}
+
+// authErrorSpecs defines the HTTP status and messages of each auth error code
+var authErrorSpecs = map[model.AuthErrorCode]authErrorSpec{
+	model.AuthErrEmailExists: {http.StatusConflict, authErrorMessages{
+		JA: "このメールアドレスは既に登録されています",
+		EN: "This email address is already registered",
+	}},
+	model.AuthErrInvalidEmail: {http.StatusBadRequest, authErrorMessages{
+		JA: "メールアドレスの形式が正しくありません",
+		EN: "The email address is invalid",
+	}},
+	model.AuthErrWeakPassword: {http.StatusBadRequest, authErrorMessages{
+		JA: "パスワードは6文字以上で入力してください",
+		EN: "Password must be at least 6 characters",
+	}},
+	model.AuthErrInvalidCredentials: {http.StatusUnauthorized, authErrorMessages{
+		JA: "メールアドレスまたはパスワードが正しくありません",
+		EN: "Incorrect email address or password",
+	}},
+	model.AuthErrTooManyAttempts: {http.StatusTooManyRequests, authErrorMessages{
+		JA: "試行回数が多すぎます。しばらくしてから再度お試しください",
+		EN: "Too many attempts. Please try again later",
+	}},
+	model.AuthErrUserDisabled: {http.StatusForbidden, authErrorMessages{
+		JA: "このアカウントは無効化されています",
+		EN: "This account has been disabled",
+	}},
+	model.AuthErrUserNotFound: {http.StatusUnauthorized, authErrorMessages{
+		JA: "アカウントが見つかりません",
+		EN: "The account was not found",
+	}},
+	model.AuthErrEmailNotFound: {http.StatusNotFound, authErrorMessages{
+		JA: "このメールアドレスは登録されていません",
+		EN: "This email address is not registered",
+	}},
+	model.AuthErrEmailNotVerified: {http.StatusForbidden, authErrorMessages{
+		JA: "メールアドレスが確認されていません。確認メールのリンクを開いてください",
+		EN: "Email not verified. Please check your email and verify your account",
+	}},
+	model.AuthErrEmailVerified: {http.StatusConflict, authErrorMessages{
+		JA: "メールアドレスは既に確認済みです",
+		EN: "Email is already verified",
+	}},
+	model.AuthErrInvalidRefreshToken: {http.StatusUnauthorized, authErrorMessages{
+		JA: "リフレッシュトークンが無効か期限切れです。再度ログインしてください",
+		EN: "Invalid or expired refresh token",
+	}},
+	model.AuthErrInvalidToken: {http.StatusUnauthorized, authErrorMessages{
+		JA: "認証トークンが無効です。再度ログインしてください",
+		EN: "Invalid token",
+	}},
+	model.AuthErrTokenRevoked: {http.StatusUnauthorized, authErrorMessages{
+		JA: "セッションは失効しています。再度ログインしてください",
+		EN: "Session has been revoked. Please sign in again",
+	}},
+	model.AuthErrCredentialTooOld: {http.StatusUnauthorized, authErrorMessages{
+		JA: "最後のログインから時間が経っています。再度ログインしてください",
+		EN: "Recent sign-in required. Please sign in again",
+	}},
+	model.AuthErrInvalidActionCode: {http.StatusBadRequest, authErrorMessages{
+		JA: "リンクが無効か期限切れです",
+		EN: "The link is invalid or has expired",
+	}},
+	model.AuthErrNotGuest: {http.StatusConflict, authErrorMessages{
+		JA: "このアカウントは既にメールアドレスで登録されています",
+		EN: "This account is already registered with an email address",
+	}},
+	model.AuthErrGuestAccount: {http.StatusForbidden, authErrorMessages{
+		JA: "ゲストアカウントでは利用できません。メールアドレスを登録してください",
+		EN: "Not available for guest accounts. Please register an email address",
+	}},
+	model.AuthErrMissingField: {http.StatusBadRequest, authErrorMessages{
+		JA: "必須項目が入力されていません",
+		EN: "A required field is missing",
+	}},
+	model.AuthErrFailed: {http.StatusBadRequest, authErrorMessages{
+		JA: "認証に失敗しました",
+		EN: "Authentication failed",
+	}},
+}
+
+// internalAuthErrorMessages are returned when the failure is not an auth error (DB, network, ...)
+var internalAuthErrorMessages = authErrorMessages{
+	JA: "サーバーでエラーが発生しました。しばらくしてから再度お試しください",
+	EN: "Internal server error",
+}
+
+// writeAuthError responds with the status and stable code of an authentication failure
+func writeAuthError(c echo.Context, err error) error {
+	authErr, ok := model.AsAuthError(err)
+	if !ok {
+		authErr = missingFieldError(err)
+	}
+	if authErr == nil {
+		return c.JSON(http.StatusInternalServerError, authErrorResponse{
+			Code:     "internal_error",
+			Error:    internalAuthErrorMessages.EN,
+			Messages: internalAuthErrorMessages,
+		})
+	}
+
+	spec, ok := authErrorSpecs[authErr.Code]
+	if !ok {
+		spec = authErrorSpecs[model.AuthErrFailed]
+	}
+	messages := spec.messages
+	if authErr.Code == model.AuthErrMissingField && authErr.Detail != "" {
+		messages.EN = authErr.Detail
+	}
+	if authErr.RetryAfter > 0 {
+		// 秒単位に切り上げる
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(authErr.RetryAfter.Seconds()))))
+	}
+	return c.JSON(spec.status, authErrorResponse{
+		Code:     authErr.Code,
+		Error:    messages.EN,
+		Messages: messages,
+	})
+}
+
+// missingFieldError converts the service's required-field errors to an AuthError
+func missingFieldError(err error) *model.AuthError {
+	for _, required := range []error{
+		service.ErrEmailRequired,
+		service.ErrRefreshTokenRequired,
+		service.ErrActionCodeRequired,
+		service.ErrEmailChangeTokenRequired,
+	} {
+		if errors.Is(err, required) {
+			return &model.AuthError{Code: model.AuthErrMissingField, Detail: required.Error()}
+		}
+	}
+	return nil
+}
+
+// logAuthError logs expected client failures as WARN and everything else as ERROR
+func logAuthError(action string, err error) {
+	if _, ok := model.AsAuthError(err); ok || missingFieldError(err) != nil {
+		log.Printf("[WARN] %s failed: %v", action, err)
+		return
+	}
+	log.Printf("[ERROR] %s failed: %v", action, err)
+}
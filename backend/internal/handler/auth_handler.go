@@ -1,21 +1,30 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 
+	"zerodelay/internal/config"
 	"zerodelay/internal/domain/model"
+	custommiddleware "zerodelay/internal/middleware"
 	"zerodelay/internal/service"
 )
 
 type AuthHandler struct {
-	authService *service.AuthService
+	authService   *service.AuthService
+	rateLimiter   *service.AuthRateLimiter
+	sessionConfig config.SessionConfig
 }
 
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService *service.AuthService, rateLimiter *service.AuthRateLimiter, sessionConfig config.SessionConfig) *AuthHandler {
+	return &AuthHandler{
+		authService:   authService,
+		rateLimiter:   rateLimiter,
+		sessionConfig: sessionConfig,
+	}
 }
 
 func (h *AuthHandler) SignUp(c echo.Context) error {
@@ -25,10 +34,14 @@ func (h *AuthHandler) SignUp(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
 
+	if err := h.rateLimiter.Allow(c.Request().Context(), service.AuthActionSignUp, c.RealIP(), req.Email); err != nil {
+		return writeAuthError(c, err)
+	}
+
 	resp, err := h.authService.SignUp(c.Request().Context(), &req)
 	if err != nil {
-		log.Printf("[ERROR] SignUp failed: %v", err)
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		logAuthError("SignUp", err)
+		return writeAuthError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, resp)
@@ -41,27 +54,224 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
 
+	// Firebase へ転送する前に IP・メールアドレスごとの試行回数とロックアウトを確認する
+	ip := c.RealIP()
+	if err := h.rateLimiter.Allow(c.Request().Context(), service.AuthActionLogin, ip, req.Email); err != nil {
+		return writeAuthError(c, err)
+	}
+
 	resp, err := h.authService.Login(c.Request().Context(), &req)
 	if err != nil {
-		log.Printf("[ERROR] Login failed: %v", err)
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		if errors.Is(err, model.ErrAuthInvalidCredentials) {
+			h.rateLimiter.RecordFailure(c.Request().Context(), service.AuthActionLogin, ip, req.Email)
+		}
+		logAuthError("Login", err)
+		return writeAuthError(c, err)
 	}
+	h.rateLimiter.RecordSuccess(c.Request().Context(), service.AuthActionLogin, req.Email)
 
+	h.issueSessionCookie(c, resp)
 	return c.JSON(http.StatusOK, resp)
 }
 
-func (h *AuthHandler) Logout(c echo.Context) error {
-	// Firebaseのログアウトはクライアント側でトークンを削除するだけで完結
-	// サーバー側ではログの記録や統計情報の更新などを行う
+// SignInAsGuest handles POST /api/v1/auth/guest
+func (h *AuthHandler) SignInAsGuest(c echo.Context) error {
+	if err := h.rateLimiter.Allow(c.Request().Context(), service.AuthActionGuest, c.RealIP(), ""); err != nil {
+		return writeAuthError(c, err)
+	}
+
+	resp, err := h.authService.SignInAsGuest(c.Request().Context())
+	if err != nil {
+		logAuthError("SignInAsGuest", err)
+		return writeAuthError(c, err)
+	}
+
+	h.issueSessionCookie(c, resp)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpgradeGuest handles POST /api/v1/auth/upgrade
+func (h *AuthHandler) UpgradeGuest(c echo.Context) error {
+	var req model.UpgradeGuestRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] UpgradeGuest bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	// Firebase の紐付けにはIDトークンが必要なため、セッションクッキーでは受け付けない
+	idToken := custommiddleware.IDToken(c)
+	if idToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Upgrading a guest requires an Authorization: Bearer ID token"})
+	}
+
+	// アカウント作成にあたるため、サインアップと同じ枠で試行回数を数える
+	if err := h.rateLimiter.Allow(c.Request().Context(), service.AuthActionSignUp, c.RealIP(), req.Email); err != nil {
+		return writeAuthError(c, err)
+	}
 
-	// 認証ミドルウェアで設定されたUIDを取得（オプション）
-	uid := c.Get("uid")
-	if uid != nil {
-		// ログ出力（必要に応じて）
-		// log.Printf("[INFO] User logged out: %s", uid)
+	uid, _ := c.Get("uid").(string)
+	anonymous, _ := c.Get("anonymous").(bool)
+	resp, err := h.authService.UpgradeGuest(c.Request().Context(), uid, anonymous, idToken, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+		}
+		logAuthError("UpgradeGuest", err)
+		return writeAuthError(c, err)
+	}
+
+	// メール確認後に改めてログインしてもらうため、ゲストのセッションは破棄する
+	clearSessionCookies(c, h.sessionConfig)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// issueSessionCookie sets the HttpOnly session cookie (ブラウザは credentials: "include" で自動送信する).
+// 発行に失敗してもIDトークンでの認証は使えるため、ログイン自体は成功とする
+func (h *AuthHandler) issueSessionCookie(c echo.Context, resp *model.AuthResponse) {
+	sessionCookie, err := h.authService.CreateSessionCookie(c.Request().Context(), resp.IDToken, h.sessionConfig.TTL)
+	if err != nil {
+		log.Printf("[WARN] Session cookie was not issued: %v", err)
+	} else if csrfToken, err := setSessionCookies(c, h.sessionConfig, sessionCookie); err != nil {
+		log.Printf("[WARN] Session cookie was not issued: %v", err)
+	} else {
+		resp.CSRFToken = csrfToken
+	}
+}
+
+// RequestPasswordReset handles POST /api/v1/auth/password-reset
+func (h *AuthHandler) RequestPasswordReset(c echo.Context) error {
+	var req model.PasswordResetRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] RequestPasswordReset bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	// 任意のアドレスへリセットメールを送り続けられないよう、確認メール再送と同じ枠で試行回数を数える
+	if err := h.rateLimiter.Allow(c.Request().Context(), service.AuthActionLogin, c.RealIP(), req.Email); err != nil {
+		return writeAuthError(c, err)
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request().Context(), &req); err != nil {
+		logAuthError("RequestPasswordReset", err)
+		return writeAuthError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
-		"message": "Logged out successfully",
+		"message": "If the email is registered, a password reset email has been sent",
 	})
 }
+
+// ResendVerification handles POST /api/v1/auth/resend-verification
+func (h *AuthHandler) ResendVerification(c echo.Context) error {
+	var req model.ResendVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] ResendVerification bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	// パスワードを検証するため、ログインと同じ枠で試行回数を数える
+	ip := c.RealIP()
+	if err := h.rateLimiter.Allow(c.Request().Context(), service.AuthActionLogin, ip, req.Email); err != nil {
+		return writeAuthError(c, err)
+	}
+
+	if err := h.authService.ResendVerification(c.Request().Context(), &req); err != nil {
+		if errors.Is(err, model.ErrAuthInvalidCredentials) {
+			h.rateLimiter.RecordFailure(c.Request().Context(), service.AuthActionLogin, ip, req.Email)
+		}
+		logAuthError("ResendVerification", err)
+		return writeAuthError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Verification email sent"})
+}
+
+// VerifyEmail handles POST /api/v1/auth/verify-email
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	var req model.ActionCodeRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] VerifyEmail bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	return h.applyEmailVerification(c, &req)
+}
+
+// HandleEmailAction handles GET /api/v1/auth/action, the link target of emails sent by the local provider
+func (h *AuthHandler) HandleEmailAction(c echo.Context) error {
+	switch c.QueryParam("mode") {
+	case "verifyEmail":
+		return h.applyEmailVerification(c, &model.ActionCodeRequest{OobCode: c.QueryParam("oobCode")})
+	case "resetPassword":
+		return c.JSON(http.StatusOK, map[string]string{
+			"message": "Send oobCode and newPassword to POST /api/v1/auth/password-reset/confirm",
+		})
+	}
+	return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unknown action mode"})
+}
+
+func (h *AuthHandler) applyEmailVerification(c echo.Context, req *model.ActionCodeRequest) error {
+	if err := h.authService.ApplyEmailVerification(c.Request().Context(), req); err != nil {
+		logAuthError("VerifyEmail", err)
+		return writeAuthError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Email verified successfully"})
+}
+
+// ConfirmPasswordReset handles POST /api/v1/auth/password-reset/confirm
+func (h *AuthHandler) ConfirmPasswordReset(c echo.Context) error {
+	var req model.ConfirmPasswordResetRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] ConfirmPasswordReset bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	if err := h.authService.ConfirmPasswordReset(c.Request().Context(), &req); err != nil {
+		logAuthError("ConfirmPasswordReset", err)
+		return writeAuthError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Password has been reset"})
+}
+
+// RefreshToken handles POST /api/v1/auth/refresh
+func (h *AuthHandler) RefreshToken(c echo.Context) error {
+	var req model.RefreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] RefreshToken bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	resp, err := h.authService.RefreshToken(c.Request().Context(), &req)
+	if err != nil {
+		logAuthError("RefreshToken", err)
+		return writeAuthError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Logout handles POST /api/v1/auth/logout.
+// リフレッシュトークンを失効させてからセッションクッキーを破棄する。
+// Firebase の失効はユーザー単位のため、ログアウトは常にすべての端末が対象になる（all_devices は互換のため受け付ける）
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req model.LogoutRequest
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			log.Printf("[WARN] Logout bind failed: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+		}
+	}
+
+	uid, _ := c.Get("uid").(string)
+
+	// リフレッシュトークンを失効させ、失効前に発行されたIDトークン・セッションクッキーも重要なルートで拒否されるようにする
+	if err := h.authService.RevokeAllSessions(c.Request().Context(), uid); err != nil {
+		log.Printf("[ERROR] Logout failed to revoke sessions for %s: %v", uid, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to sign out"})
+	}
+
+	// IDトークン・リフレッシュトークンはクライアント側でも削除する。セッションクッキーはここで破棄する
+	clearSessionCookies(c, h.sessionConfig)
+
+	log.Printf("[INFO] User logged out from all devices: %s", uid)
+	return c.JSON(http.StatusOK, map[string]string{"message": "Logged out from all devices successfully"})
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/service"
+)
+
+// EmailChangeHandler handles HTTP requests for verified email changes
+type EmailChangeHandler struct {
+	emailChangeService *service.EmailChangeService
+}
+
+// NewEmailChangeHandler creates a new email change handler
+func NewEmailChangeHandler(emailChangeService *service.EmailChangeService) *EmailChangeHandler {
+	return &EmailChangeHandler{emailChangeService: emailChangeService}
+}
+
+// RequestChange handles POST /api/v1/users/me/email
+func (h *EmailChangeHandler) RequestChange(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var req model.ChangeEmailRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] RequestEmailChange bind failed for UID %s: %v", firebaseUID, err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	change, err := h.emailChangeService.RequestChange(c.Request().Context(), firebaseUID, req.Email)
+	if err != nil {
+		return h.respondError(c, "RequestEmailChange", err)
+	}
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"message":       "A confirmation link has been sent to the new email address",
+		"pending_email": change.NewEmail,
+		"expires_at":    change.ExpiresAt,
+	})
+}
+
+// GetPending handles GET /api/v1/users/me/email
+func (h *EmailChangeHandler) GetPending(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	change, err := h.emailChangeService.PendingChange(firebaseUID)
+	if err != nil {
+		return h.respondError(c, "GetPendingEmailChange", err)
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+// CancelChange handles DELETE /api/v1/users/me/email
+func (h *EmailChangeHandler) CancelChange(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	if err := h.emailChangeService.CancelChange(firebaseUID); err != nil {
+		return h.respondError(c, "CancelEmailChange", err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ConfirmChange handles POST /api/v1/auth/email-change/confirm.
+// JSON で呼ばれた場合は JSON を、確認ページのフォームから送信された場合は結果のページを返す
+func (h *EmailChangeHandler) ConfirmChange(c echo.Context) error {
+	fromPage := strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationForm)
+
+	var req model.ConfirmEmailChangeRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] ConfirmEmailChange bind failed: %v", err)
+		if fromPage {
+			return renderEmailChangePage(c, http.StatusBadRequest, emailChangePage{Message: "リクエストが不正です"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	if err := h.emailChangeService.ConfirmChange(c.Request().Context(), req.Token); err != nil {
+		logAuthError("ConfirmEmailChange", err)
+		if fromPage {
+			status, message := http.StatusInternalServerError, "メールアドレスの変更に失敗しました"
+			if authErr, ok := model.AsAuthError(err); ok {
+				if spec, ok := authErrorSpecs[authErr.Code]; ok {
+					status, message = spec.status, spec.messages.JA
+				}
+			}
+			return renderEmailChangePage(c, status, emailChangePage{Message: message})
+		}
+		return writeAuthError(c, err)
+	}
+
+	if fromPage {
+		return renderEmailChangePage(c, http.StatusOK, emailChangePage{Message: "メールアドレスを変更しました"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Email address has been changed"})
+}
+
+// ConfirmLink handles GET /api/v1/auth/email-change/confirm, the link target of the confirmation email.
+// GET では変更せず、トークンを POST する確認ボタンのページを返す
+// （メールのリンク先を自動で開くスキャナーやプレビューで変更が確定しないようにする）
+func (h *EmailChangeHandler) ConfirmLink(c echo.Context) error {
+	token := strings.TrimSpace(c.QueryParam("token"))
+	if token == "" {
+		return renderEmailChangePage(c, http.StatusBadRequest, emailChangePage{Message: authErrorSpecs[model.AuthErrInvalidActionCode].messages.JA})
+	}
+	return renderEmailChangePage(c, http.StatusOK, emailChangePage{Token: token})
+}
+
+// emailChangePage is the data of the confirmation / result page (Token が空なら結果の表示)
+type emailChangePage struct {
+	Token   string
+	Message string
+}
+
+var emailChangePageTemplate = template.Must(template.New("email-change").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="referrer" content="no-referrer">
+<title>メールアドレスの変更 - ZeroDelay</title>
+</head>
+<body>
+<h1>メールアドレスの変更</h1>
+{{if .Token}}<p>ボタンを押すと、ZeroDelay のメールアドレスの変更が完了します。</p>
+<form method="post" action="">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">メールアドレスを変更する</button>
+</form>{{else}}<p>{{.Message}}</p>{{end}}
+</body>
+</html>
+`))
+
+func renderEmailChangePage(c echo.Context, status int, page emailChangePage) error {
+	var buf bytes.Buffer
+	if err := emailChangePageTemplate.Execute(&buf, page); err != nil {
+		return err
+	}
+	// トークンを含むページをキャッシュさせない
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.HTML(status, buf.String())
+}
+
+// respondError maps email change errors to HTTP responses
+func (h *EmailChangeHandler) respondError(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+	case errors.Is(err, service.ErrEmailChangeNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "No pending email change"})
+	case errors.Is(err, service.ErrEmailUnchanged):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, service.ErrMailerUnavailable):
+		log.Printf("[WARN] %s failed: %v", action, err)
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Email change is unavailable because no mail server is configured"})
+	}
+	if _, ok := model.AsAuthError(err); ok {
+		log.Printf("[WARN] %s failed: %v", action, err)
+		return writeAuthError(c, err)
+	}
+	log.Printf("[ERROR] %s failed: %v", action, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "メールアドレス変更の処理に失敗しました"})
+}
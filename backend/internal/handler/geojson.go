@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/domain/model"
+)
+
+// wantsGeoJSON reports whether the client asked for GeoJSON via the Accept header
+func wantsGeoJSON(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), model.GeoJSONMediaType)
+}
+
+// respondGeoJSON writes a FeatureCollection with the GeoJSON media type
+func respondGeoJSON(c echo.Context, fc *model.FeatureCollection) error {
+	body, err := json.Marshal(fc)
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, model.GeoJSONMediaType, body)
+}
+
+// placesToFeatureCollection renders places as Point features carrying every place attribute
+func placesToFeatureCollection(places []model.Place) (*model.FeatureCollection, error) {
+	features := make([]model.Feature, 0, len(places))
+	for _, place := range places {
+		geometry, err := model.NewPointGeometry(place.Lon, place.Lat)
+		if err != nil {
+			return nil, err
+		}
+
+		// JSON表現をそのままプロパティにする（座標はgeometryに含まれるため除外）
+		raw, err := json.Marshal(place)
+		if err != nil {
+			return nil, err
+		}
+		var properties map[string]interface{}
+		if err := json.Unmarshal(raw, &properties); err != nil {
+			return nil, err
+		}
+		delete(properties, "lat")
+		delete(properties, "lon")
+
+		features = append(features, model.Feature{
+			Type:       "Feature",
+			ID:         place.ID,
+			Geometry:   geometry,
+			Properties: properties,
+		})
+	}
+	return model.NewFeatureCollection(features), nil
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/service"
+)
+
+// HazardHandler handles HTTP requests for hazard information
+type HazardHandler struct {
+	hazardService *service.HazardService
+}
+
+// NewHazardHandler creates a new hazard handler
+func NewHazardHandler(hazardService *service.HazardService) *HazardHandler {
+	return &HazardHandler{hazardService: hazardService}
+}
+
+// GetHazardsAt handles GET /api/v1/hazards/at
+func (h *HazardHandler) GetHazardsAt(c echo.Context) error {
+	lat, err := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid lat"})
+	}
+	lon, err := strconv.ParseFloat(c.QueryParam("lon"), 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid lon"})
+	}
+
+	assessment, err := h.hazardService.AssessPoint(lat, lon)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCoordinates) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid coordinates"})
+		}
+		log.Printf("[ERROR] GetHazardsAt failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ハザード情報の取得に失敗しました"})
+	}
+
+	return c.JSON(http.StatusOK, assessment)
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 
@@ -25,12 +26,15 @@ func NewPlaceHandler(placeService *service.PlaceService) *PlaceHandler {
 // CreatePlace handles POST /api/places
 func (h *PlaceHandler) CreatePlace(c echo.Context) error {
 	var place model.Place
-	if err := c.Bind(&place); err != nil {
+	if err := bindPlace(c, &place); err != nil {
 		log.Printf("[WARN] CreatePlace bind failed: %v", err)
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": placeBindMessage(err)})
 	}
 
 	if err := h.placeService.CreatePlace(&place); err != nil {
+		if msg, ok := placeValidationMessage(err); ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
 		log.Printf("[ERROR] CreatePlace failed: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "場所の作成に失敗しました"})
 	}
@@ -58,10 +62,84 @@ func (h *PlaceHandler) GetPlace(c echo.Context) error {
 }
 
 // GetAllPlaces handles GET /api/places
+// Accept: application/geo+json の場合は GeoJSON で返す
 func (h *PlaceHandler) GetAllPlaces(c echo.Context) error {
-	places, err := h.placeService.GetAllPlaces()
+	return h.listPlaces(c, wantsGeoJSON(c))
+}
+
+// GetPlacesGeoJSON handles GET /api/places.geojson
+func (h *PlaceHandler) GetPlacesGeoJSON(c echo.Context) error {
+	return h.listPlaces(c, true)
+}
+
+func (h *PlaceHandler) listPlaces(c echo.Context, asGeoJSON bool) error {
+	filter, err := parsePlaceFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	places, err := h.placeService.SearchPlaces(filter)
+	if err != nil {
+		if msg, ok := placeValidationMessage(err); ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		log.Printf("[ERROR] listPlaces failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "場所の取得に失敗しました"})
+	}
+
+	if asGeoJSON {
+		return h.respondPlacesGeoJSON(c, places)
+	}
+	return c.JSON(http.StatusOK, places)
+}
+
+func (h *PlaceHandler) respondPlacesGeoJSON(c echo.Context, places []model.Place) error {
+	fc, err := placesToFeatureCollection(places)
+	if err != nil {
+		log.Printf("[ERROR] Failed to render places as GeoJSON: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "場所の取得に失敗しました"})
+	}
+	return respondGeoJSON(c, fc)
+}
+
+// GetNearbyPlaces handles GET /api/places/nearby
+func (h *PlaceHandler) GetNearbyPlaces(c echo.Context) error {
+	lat, err := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid lat"})
+	}
+	lon, err := strconv.ParseFloat(c.QueryParam("lon"), 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid lon"})
+	}
+
+	filter, err := parsePlaceFilter(c)
 	if err != nil {
-		log.Printf("[ERROR] GetAllPlaces failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	query := model.NearbyQuery{Lat: lat, Lon: lon, Filter: filter}
+	if raw := c.QueryParam("radius"); raw != "" {
+		radius, err := strconv.ParseFloat(raw, 64)
+		if err != nil || radius <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid radius"})
+		}
+		query.RadiusMeters = radius
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit"})
+		}
+		query.Limit = limit
+	}
+
+	places, err := h.placeService.NearbyPlaces(query)
+	if err != nil {
+		if msg, ok := placeValidationMessage(err); ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		log.Printf("[ERROR] GetNearbyPlaces failed: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "場所の取得に失敗しました"})
 	}
 
@@ -76,13 +154,19 @@ func (h *PlaceHandler) UpdatePlace(c echo.Context) error {
 	}
 
 	var place model.Place
-	if err := c.Bind(&place); err != nil {
+	if err := bindPlace(c, &place); err != nil {
 		log.Printf("[WARN] UpdatePlace bind failed: %v", err)
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": placeBindMessage(err)})
 	}
 	place.ID = uint(id)
 
 	if err := h.placeService.UpdatePlace(&place); err != nil {
+		if msg, ok := placeValidationMessage(err); ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		if errors.Is(err, service.ErrPlaceNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Place not found"})
+		}
 		log.Printf("[ERROR] UpdatePlace failed: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "場所の更新に失敗しました"})
 	}
@@ -90,6 +174,63 @@ func (h *PlaceHandler) UpdatePlace(c echo.Context) error {
 	return c.JSON(http.StatusOK, place)
 }
 
+// UpdateOccupancy handles PUT /api/places/:id/occupancy
+func (h *PlaceHandler) UpdateOccupancy(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid place ID"})
+	}
+
+	var req model.UpdateOccupancyRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] UpdateOccupancy bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	uid, _ := c.Get("uid").(string)
+	place, err := h.placeService.UpdateOccupancy(uint(id), &req, uid)
+	if err != nil {
+		if msg, ok := placeValidationMessage(err); ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+		}
+		if errors.Is(err, service.ErrPlaceNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Place not found"})
+		}
+		log.Printf("[ERROR] UpdateOccupancy failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "収容状況の更新に失敗しました"})
+	}
+
+	log.Printf("[INFO] Occupancy updated for place %d by %s: status=%s %d/%d", place.ID, uid, place.Status, place.Occupancy, place.Capacity)
+	return c.JSON(http.StatusOK, place)
+}
+
+// GetOccupancyHistory handles GET /api/places/:id/occupancy/history
+func (h *PlaceHandler) GetOccupancyHistory(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid place ID"})
+	}
+
+	limit := 0
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit"})
+		}
+	}
+
+	history, err := h.placeService.GetOccupancyHistory(uint(id), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrPlaceNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Place not found"})
+		}
+		log.Printf("[ERROR] GetOccupancyHistory failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "収容状況履歴の取得に失敗しました"})
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
 // DeletePlace handles DELETE /api/places/:id
 func (h *PlaceHandler) DeletePlace(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -104,3 +245,120 @@ func (h *PlaceHandler) DeletePlace(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Place deleted successfully"})
 }
+
+// parsePlaceFilter reads list filters from the query string
+func parsePlaceFilter(c echo.Context) (model.PlaceFilter, error) {
+	var filter model.PlaceFilter
+
+	if raw := c.QueryParam("bbox"); raw != "" {
+		bbox, err := parseBoundingBox(raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.BBox = bbox
+	}
+	for _, v := range splitQueryList(c.QueryParam("category")) {
+		filter.Categories = append(filter.Categories, model.PlaceCategory(v))
+	}
+	for _, v := range splitQueryList(c.QueryParam("disaster_type")) {
+		filter.DisasterTypes = append(filter.DisasterTypes, model.DisasterType(v))
+	}
+	for _, v := range splitQueryList(c.QueryParam("status")) {
+		filter.Statuses = append(filter.Statuses, model.PlaceStatus(v))
+	}
+	if raw := c.QueryParam("has_vacancy"); raw != "" {
+		hasVacancy, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, errors.New("invalid has_vacancy: must be true or false")
+		}
+		filter.HasVacancy = hasVacancy
+	}
+
+	return filter, nil
+}
+
+// splitQueryList splits a comma-separated query value, dropping empty items
+func splitQueryList(raw string) []string {
+	var result []string
+	for _, p := range strings.Split(raw, ",") {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+// placeValidationMessage maps service validation errors to client-facing messages
+// errMissingCoordinates is returned by bindPlace when lat or lon is missing from the body
+var errMissingCoordinates = errors.New("lat and lon are required")
+
+// placeRequest shadows Place.Lat / Lon with pointers to tell a missing coordinate from 0
+type placeRequest struct {
+	model.Place
+	Lat *float64 `json:"lat"`
+	Lon *float64 `json:"lon"`
+}
+
+// bindPlace binds a place from the request body and requires both coordinates.
+// 省略された座標が 0 として (0, 0) に保存されないようにする
+func bindPlace(c echo.Context, place *model.Place) error {
+	var req placeRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if req.Lat == nil || req.Lon == nil {
+		return errMissingCoordinates
+	}
+	*place = req.Place
+	place.Lat, place.Lon = *req.Lat, *req.Lon
+	return nil
+}
+
+func placeBindMessage(err error) string {
+	if errors.Is(err, errMissingCoordinates) {
+		return err.Error()
+	}
+	return "Invalid request body"
+}
+
+func placeValidationMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCoordinates):
+		return "Invalid coordinates", true
+	case errors.Is(err, service.ErrInvalidBoundingBox):
+		return "Invalid bbox", true
+	case errors.Is(err, service.ErrInvalidCategory):
+		return "Invalid category", true
+	case errors.Is(err, service.ErrInvalidDisaster):
+		return "Invalid disaster_type", true
+	case errors.Is(err, service.ErrInvalidStatus):
+		return "Invalid status", true
+	case errors.Is(err, service.ErrInvalidOccupancy):
+		return "Capacity and occupancy must not be negative", true
+	}
+	return "", false
+}
+
+// parseBoundingBox parses "minLon,minLat,maxLon,maxLat"
+func parseBoundingBox(raw string) (*model.BoundingBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("invalid bbox: expected minLon,minLat,maxLon,maxLat")
+	}
+
+	values := make([]float64, 4)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, errors.New("invalid bbox: values must be numbers")
+		}
+		values[i] = v
+	}
+
+	return &model.BoundingBox{
+		MinLon: values[0],
+		MinLat: values[1],
+		MaxLon: values[2],
+		MaxLat: values[3],
+	}, nil
+}
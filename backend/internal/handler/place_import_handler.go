@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/service"
+)
+
+// maxImportFileSize limits the size of an uploaded CSV (32MB)
+const maxImportFileSize = 32 << 20
+
+// maxImportRequestSize leaves room for the multipart headers around the CSV
+const maxImportRequestSize = maxImportFileSize + 1<<20
+
+// PlaceImportHandler handles bulk import of places
+type PlaceImportHandler struct {
+	importService *service.PlaceImportService
+}
+
+// NewPlaceImportHandler creates a new place import handler
+func NewPlaceImportHandler(importService *service.PlaceImportService) *PlaceImportHandler {
+	return &PlaceImportHandler{importService: importService}
+}
+
+// ImportPlaces handles POST /api/v1/admin/places/import
+// CSVは multipart の "file" フィールド、またはリクエストボディ（text/csv）で受け付ける
+func (h *PlaceImportHandler) ImportPlaces(c echo.Context) error {
+	opts := model.PlaceImportOptions{
+		Category: model.PlaceCategory(c.QueryParam("category")),
+	}
+	if raw := c.QueryParam("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid dry_run"})
+		}
+		opts.DryRun = dryRun
+	}
+
+	// 上限を超えたファイルは途中で切り詰めず 413 を返す
+	req := c.Request()
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, maxImportRequestSize)
+	} else {
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, maxImportFileSize)
+	}
+
+	var src io.Reader = req.Body
+	fileHeader, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Import file is too large (max 32MB)"})
+	}
+	if err == nil {
+		if fileHeader.Size > maxImportFileSize {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Import file is too large (max 32MB)"})
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Printf("[WARN] ImportPlaces failed to open uploaded file: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid upload"})
+		}
+		defer file.Close()
+		src = file
+	}
+
+	report, err := h.importService.ImportCSV(src, opts)
+	if err != nil {
+		if errors.As(err, &maxBytesErr) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Import file is too large (max 32MB)"})
+		}
+		if errors.Is(err, service.ErrInvalidImportFile) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, service.ErrInvalidCategory) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid category"})
+		}
+		log.Printf("[ERROR] ImportPlaces failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "場所データの取込に失敗しました"})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
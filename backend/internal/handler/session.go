@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/config"
+)
+
+// setSessionCookies sets the HttpOnly session cookie and the CSRF cookie, and returns the CSRF token.
+// CSRF トークンは JavaScript から読めるクッキーにも入れる（ダブルサブミット方式）
+func setSessionCookies(c echo.Context, cfg config.SessionConfig, sessionCookie string) (string, error) {
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(cfg.TTL)
+	c.SetCookie(&http.Cookie{
+		Name:     config.SessionCookieName,
+		Value:    sessionCookie,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		Expires:  expires,
+		MaxAge:   int(cfg.TTL.Seconds()),
+		HttpOnly: true,
+		Secure:   cfg.Secure,
+		SameSite: cfg.SameSite,
+	})
+	c.SetCookie(&http.Cookie{
+		Name:     config.CSRFCookieName,
+		Value:    csrfToken,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		Expires:  expires,
+		MaxAge:   int(cfg.TTL.Seconds()),
+		HttpOnly: false,
+		Secure:   cfg.Secure,
+		SameSite: cfg.SameSite,
+	})
+	return csrfToken, nil
+}
+
+// clearSessionCookies expires the session and CSRF cookies
+func clearSessionCookies(c echo.Context, cfg config.SessionConfig) {
+	for _, name := range []string{config.SessionCookieName, config.CSRFCookieName} {
+		c.SetCookie(&http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			Domain:   cfg.Domain,
+			Expires:  time.Unix(0, 0),
+			MaxAge:   -1,
+			HttpOnly: name == config.SessionCookieName,
+			Secure:   cfg.Secure,
+			SameSite: cfg.SameSite,
+		})
+	}
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate CSRF token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
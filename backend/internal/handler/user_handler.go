@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -30,6 +31,9 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 	}
 
 	if err := h.userService.CreateUser(&user); err != nil {
+		if errors.Is(err, service.ErrInvalidRole) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid role"})
+		}
 		log.Printf("[ERROR] CreateUser failed: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ユーザーの作成に失敗しました"})
 	}
@@ -86,13 +90,24 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 }
 
 // DeleteUser handles DELETE /api/users/:id
+// ?delete_auth_account=true を指定した場合のみ Firebase のアカウントも削除する
 func (h *UserHandler) DeleteUser(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
 	}
+	var deleteAuthAccount bool
+	if raw := c.QueryParam("delete_auth_account"); raw != "" {
+		deleteAuthAccount, err = strconv.ParseBool(raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid delete_auth_account"})
+		}
+	}
 
-	if err := h.userService.DeleteUser(uint(id)); err != nil {
+	if err := h.userService.DeleteUser(c.Request().Context(), uint(id), deleteAuthAccount); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+		}
 		log.Printf("[ERROR] DeleteUser failed: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ユーザーの削除に失敗しました"})
 	}
@@ -100,6 +115,41 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"message": "User deleted successfully"})
 }
 
+// GetMe handles GET /api/v1/users/me
+func (h *UserHandler) GetMe(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	user, err := h.userService.GetUserByFirebaseUID(firebaseUID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+		}
+		log.Printf("[ERROR] GetMe failed for UID %s: %v", firebaseUID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ユーザーの取得に失敗しました"})
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// DeleteMe handles DELETE /api/v1/users/me
+func (h *UserHandler) DeleteMe(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	if err := h.userService.DeleteAccount(c.Request().Context(), firebaseUID); err != nil {
+		log.Printf("[ERROR] DeleteMe failed for UID %s: %v", firebaseUID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "アカウントの削除に失敗しました"})
+	}
+
+	log.Printf("[INFO] Account deleted by owner: %s", firebaseUID)
+	return c.JSON(http.StatusOK, map[string]string{"message": "Account deleted successfully"})
+}
+
 // UpdateProfile handles PATCH /api/v1/users/me
 func (h *UserHandler) UpdateProfile(c echo.Context) error {
 	// ミドルウェアでセットされたFirebaseUIDを取得
@@ -122,8 +172,43 @@ func (h *UserHandler) UpdateProfile(c echo.Context) error {
 	user, err := h.userService.UpdateProfile(c.Request().Context(), firebaseUID, &req)
 	if err != nil {
 		log.Printf("[ERROR] UpdateProfile failed for UID %s: %v", firebaseUID, err)
+		if _, ok := model.AsAuthError(err); ok {
+			return writeAuthError(c, err)
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
 	return c.JSON(http.StatusOK, user)
 }
+
+// UpdateRole handles PUT /api/v1/users/:id/role
+func (h *UserHandler) UpdateRole(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	var req model.UpdateRoleRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] UpdateRole bind failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	actorUID, _ := c.Get("uid").(string)
+	user, err := h.userService.UpdateRole(actorUID, uint(id), req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRole):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid role"})
+		case errors.Is(err, service.ErrRoleSelfChange):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "You cannot change your own role"})
+		case errors.Is(err, service.ErrUserNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+		}
+		log.Printf("[ERROR] UpdateRole failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "ロールの更新に失敗しました"})
+	}
+
+	log.Printf("[INFO] Role of user %d changed to %s by %s", user.ID, user.Role, actorUID)
+	return c.JSON(http.StatusOK, user)
+}
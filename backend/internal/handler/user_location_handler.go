@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/service"
+)
+
+// UserLocationHandler handles HTTP requests for the caller's saved locations
+type UserLocationHandler struct {
+	locationService *service.UserLocationService
+}
+
+// NewUserLocationHandler creates a new saved location handler
+func NewUserLocationHandler(locationService *service.UserLocationService) *UserLocationHandler {
+	return &UserLocationHandler{locationService: locationService}
+}
+
+// ListLocations handles GET /api/v1/users/me/locations
+func (h *UserLocationHandler) ListLocations(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	locations, err := h.locationService.ListLocations(firebaseUID)
+	if err != nil {
+		return h.respondError(c, "ListLocations", err)
+	}
+	return c.JSON(http.StatusOK, locations)
+}
+
+// GetLocation handles GET /api/v1/users/me/locations/:locationId
+func (h *UserLocationHandler) GetLocation(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+	id, err := strconv.ParseUint(c.Param("locationId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid location ID"})
+	}
+
+	location, err := h.locationService.GetLocation(firebaseUID, uint(id))
+	if err != nil {
+		return h.respondError(c, "GetLocation", err)
+	}
+	return c.JSON(http.StatusOK, location)
+}
+
+// CreateLocation handles POST /api/v1/users/me/locations
+func (h *UserLocationHandler) CreateLocation(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var req model.UserLocationRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] CreateLocation bind failed for UID %s: %v", firebaseUID, err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	location, err := h.locationService.CreateLocation(firebaseUID, &req)
+	if err != nil {
+		return h.respondError(c, "CreateLocation", err)
+	}
+	return c.JSON(http.StatusCreated, location)
+}
+
+// UpdateLocation handles PUT /api/v1/users/me/locations/:locationId
+func (h *UserLocationHandler) UpdateLocation(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+	id, err := strconv.ParseUint(c.Param("locationId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid location ID"})
+	}
+
+	var req model.UserLocationRequest
+	if err := c.Bind(&req); err != nil {
+		log.Printf("[WARN] UpdateLocation bind failed for UID %s: %v", firebaseUID, err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	location, err := h.locationService.UpdateLocation(firebaseUID, uint(id), &req)
+	if err != nil {
+		return h.respondError(c, "UpdateLocation", err)
+	}
+	return c.JSON(http.StatusOK, location)
+}
+
+// DeleteLocation handles DELETE /api/v1/users/me/locations/:locationId
+func (h *UserLocationHandler) DeleteLocation(c echo.Context) error {
+	firebaseUID, ok := firebaseUIDFromContext(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+	id, err := strconv.ParseUint(c.Param("locationId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid location ID"})
+	}
+
+	if err := h.locationService.DeleteLocation(firebaseUID, uint(id)); err != nil {
+		return h.respondError(c, "DeleteLocation", err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Location deleted successfully"})
+}
+
+func (h *UserLocationHandler) respondError(c echo.Context, op string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidLocation):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, service.ErrTooManyLocations):
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Location limit reached"})
+	case errors.Is(err, service.ErrLocationNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Location not found"})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+	}
+	log.Printf("[ERROR] %s failed: %v", op, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "登録地点の処理に失敗しました"})
+}
+
+// firebaseUIDFromContext returns the UID set by the auth middleware
+func firebaseUIDFromContext(c echo.Context) (string, bool) {
+	uid, ok := c.Get("uid").(string)
+	return uid, ok && uid != ""
+}
@@ -0,0 +1,142 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Hook is a subsystem that is started with the application and stopped on shutdown.
+// Start は処理を開始したらすぐに戻る（長く動く処理は goroutine で動かす）
+type Hook struct {
+	Name string
+	// Start is called in registration order (nil = nothing to start)
+	Start func(ctx context.Context) error
+	// Stop is called in reverse registration order; ctx carries the shutdown deadline (nil = nothing to stop)
+	Stop func(ctx context.Context) error
+}
+
+// Manager starts the registered hooks, waits for SIGINT / SIGTERM or a fatal error,
+// and then stops the hooks in reverse order within the shutdown timeout.
+// 後に登録したものから止めるため、DB などの依存先を先に、HTTP サーバーを最後に登録する
+type Manager struct {
+	shutdownTimeout time.Duration
+	hooks           []Hook
+
+	failOnce sync.Once
+	failed   chan error
+}
+
+// New creates a lifecycle manager
+func New(shutdownTimeout time.Duration) *Manager {
+	return &Manager{
+		shutdownTimeout: shutdownTimeout,
+		failed:          make(chan error, 1),
+	}
+}
+
+// Append registers a hook. Hooks must be registered before Run
+func (m *Manager) Append(hook Hook) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// Fail reports an unrecoverable error from a running subsystem and starts the shutdown.
+// 2回目以降の呼び出しは無視する
+func (m *Manager) Fail(err error) {
+	m.failOnce.Do(func() {
+		m.failed <- err
+	})
+}
+
+// Go registers a background worker. run must return when its context is cancelled;
+// the worker is stopped by cancelling the context and waiting for run to return.
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	m.Append(Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("worker did not stop in time: %w", ctx.Err())
+			}
+		},
+	})
+}
+
+// Run starts every hook and blocks until a signal arrives or Fail is called, then shuts down.
+// シグナルによる正常終了なら nil を、起動失敗や Fail で終了した場合はそのエラーを返す
+func (m *Manager) Run(ctx context.Context) error {
+	ctx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	// 1. 登録順に起動（失敗したら起動済みのものだけ止める）
+	started := 0
+	var runErr error
+	for _, hook := range m.hooks {
+		if hook.Start != nil {
+			if err := hook.Start(ctx); err != nil {
+				runErr = fmt.Errorf("failed to start %s: %w", hook.Name, err)
+				break
+			}
+		}
+		started++
+	}
+
+	// 2. シグナルか致命的なエラーを待つ
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+			log.Println("[INFO] Shutdown signal received")
+		case err := <-m.failed:
+			runErr = err
+			log.Printf("[ERROR] Shutting down after a fatal error: %v", err)
+		}
+	}
+	// 停止中に2回目のシグナルを受けたら即座に終了できるよう、シグナルの捕捉をやめる
+	stopSignals()
+
+	// 3. 逆順に停止（全体で shutdownTimeout まで待つ）
+	stopCtx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	var stopErrs []error
+	for i := started - 1; i >= 0; i-- {
+		hook := m.hooks[i]
+		if hook.Stop == nil {
+			continue
+		}
+		if err := hook.Stop(stopCtx); err != nil {
+			log.Printf("[ERROR] Failed to stop %s: %v", hook.Name, err)
+			stopErrs = append(stopErrs, fmt.Errorf("%s: %w", hook.Name, err))
+			continue
+		}
+		log.Printf("[INFO] Stopped %s", hook.Name)
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+	return errors.Join(stopErrs...)
+}
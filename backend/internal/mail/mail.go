@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer writes emails to the server log instead of sending them.
+// オフライン環境（ワークショップ・防災訓練・CI）で確認リンクなどを取り出すための代替実装
+type LogMailer struct{}
+
+// NewLogMailer creates a mailer that only logs messages
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	var b strings.Builder
+	b.WriteString("[MAIL] ---------------------------------------------\n")
+	b.WriteString("To: " + msg.To + "\n")
+	b.WriteString("Subject: " + msg.Subject + "\n\n")
+	b.WriteString(msg.Body + "\n")
+	b.WriteString("-----------------------------------------------------")
+	log.Println(b.String())
+	return nil
+}
@@ -1,54 +1,147 @@
 package middleware
 
 import (
-"log"
-"net/http"
-"strings"
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
 
-"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4"
 
-"zerodelay/internal/service"
+	"zerodelay/internal/config"
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/service"
 )
 
-func FirebaseAuthMiddleware(authService *service.AuthService) echo.MiddlewareFunc {
-return func(next echo.HandlerFunc) echo.HandlerFunc {
-return func(c echo.Context) error {
-authHeader := c.Request().Header.Get("Authorization")
-if authHeader == "" {
-log.Printf("[WARN] Authorization header missing for request: %s %s", c.Request().Method, c.Request().URL.Path)
-return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Authorization header missing"})
-}
+// Context keys of the credential the request was authenticated with
+const (
+	idTokenKey       = "id_token"
+	sessionCookieKey = "session_cookie"
+)
 
-parts := strings.SplitN(authHeader, " ", 2)
-if len(parts) != 2 || parts[0] != "Bearer" {
-log.Printf("[WARN] Invalid Authorization header format for request: %s %s", c.Request().Method, c.Request().URL.Path)
-return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid Authorization header format"})
+// IDToken returns the Bearer ID token the request was authenticated with ("" for session cookies)
+func IDToken(c echo.Context) string {
+	idToken, _ := c.Get(idTokenKey).(string)
+	return idToken
 }
 
-idToken := parts[1]
+// FirebaseAuthMiddleware authenticates the request with either an
+// "Authorization: Bearer <idToken>" header or the HttpOnly session cookie.
+// セッションクッキーで認証する状態変更リクエストには CSRF トークンを要求する。
+func FirebaseAuthMiddleware(authService *service.AuthService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var (
+				token *model.TokenInfo
+				err   error
+			)
+
+			authHeader := c.Request().Header.Get("Authorization")
+			switch {
+			case authHeader != "":
+				parts := strings.SplitN(authHeader, " ", 2)
+				if len(parts) != 2 || parts[0] != "Bearer" {
+					log.Printf("[WARN] Invalid Authorization header format for request: %s %s", c.Request().Method, c.Request().URL.Path)
+					return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid Authorization header format"})
+				}
 
-uid, err := authService.VerifyIDToken(c.Request().Context(), idToken)
-if err != nil {
-log.Printf("[WARN] Token verification failed for request: %s %s - %v", c.Request().Method, c.Request().URL.Path, err)
-return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired ID token"})
-}
+				idToken := parts[1]
+
+				token, err = authService.VerifyIDToken(c.Request().Context(), idToken)
+				if err != nil {
+					log.Printf("[WARN] Token verification failed for request: %s %s - %v", c.Request().Method, c.Request().URL.Path, err)
+					return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired ID token"})
+				}
+				c.Set(idTokenKey, idToken)
+
+			case hasCookie(c, config.SessionCookieName):
+				if requiresCSRFCheck(c.Request().Method) && !validCSRFToken(c) {
+					log.Printf("[WARN] CSRF token mismatch for request: %s %s", c.Request().Method, c.Request().URL.Path)
+					return c.JSON(http.StatusForbidden, map[string]string{"error": "Invalid or missing CSRF token"})
+				}
 
-// メールアドレス確認済みかチェック
-emailVerified, err := authService.IsEmailVerified(c.Request().Context(), uid)
-if err != nil {
-log.Printf("[ERROR] Failed to check email verification status for %s: %v", uid, err)
-return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to verify email status"})
+				sessionCookie, _ := c.Cookie(config.SessionCookieName)
+				token, err = authService.VerifySessionCookie(c.Request().Context(), sessionCookie.Value)
+				if err != nil {
+					log.Printf("[WARN] Session cookie verification failed for request: %s %s - %v", c.Request().Method, c.Request().URL.Path, err)
+					return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired session"})
+				}
+				c.Set(sessionCookieKey, sessionCookie.Value)
+
+			default:
+				log.Printf("[WARN] Authorization header missing for request: %s %s", c.Request().Method, c.Request().URL.Path)
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Authorization header missing"})
+			}
+
+			uid := token.UID
+
+			// メールアドレス確認済みかチェック（通常はトークンのクレームのみで判定し、Firebase へは問い合わせない）
+			// ゲスト（匿名ログイン）は登録するまで確認不要
+			emailVerified, err := authService.IsEmailVerified(c.Request().Context(), token)
+			if err != nil {
+				log.Printf("[ERROR] Failed to check email verification status for %s: %v", uid, err)
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Failed to verify email status"})
+			}
+
+			if !emailVerified {
+				log.Printf("[WARN] Email not verified for user %s", uid)
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Email not verified. Please verify your email address"})
+			}
+
+			log.Printf("[INFO] Authentication successful for request: %s %s", c.Request().Method, c.Request().URL.Path)
+			log.Printf("[DEBUG] Authenticated user %s for request: %s %s", uid, c.Request().Method, c.Request().URL.Path)
+			c.Set("uid", uid)
+			c.Set("anonymous", token.Anonymous)
+			return next(c)
+		}
+	}
 }
 
-if !emailVerified {
-log.Printf("[WARN] Email not verified for user %s", uid)
-return c.JSON(http.StatusForbidden, map[string]string{"error": "Email not verified. Please verify your email address"})
+// RequireNotRevoked rejects credentials issued before the user's sessions were revoked
+// (e.g. after "sign out of all devices"). Firebase への問い合わせが発生するため、
+// アカウント・個人情報・管理操作などの重要なルートにのみ適用する。
+// FirebaseAuthMiddleware must run before this middleware.
+func RequireNotRevoked(authService *service.AuthService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var err error
+			if idToken, ok := c.Get(idTokenKey).(string); ok {
+				_, err = authService.VerifyIDTokenAndCheckRevoked(c.Request().Context(), idToken)
+			} else if sessionCookie, ok := c.Get(sessionCookieKey).(string); ok {
+				_, err = authService.VerifySessionCookieAndCheckRevoked(c.Request().Context(), sessionCookie)
+			} else {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+			}
+
+			if err != nil {
+				log.Printf("[WARN] Revoked or invalid credential for request: %s %s - %v", c.Request().Method, c.Request().URL.Path, err)
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Session has been revoked. Please log in again"})
+			}
+			return next(c)
+		}
+	}
 }
 
-log.Printf("[INFO] Authentication successful for request: %s %s", c.Request().Method, c.Request().URL.Path)
-log.Printf("[DEBUG] Authenticated user %s for request: %s %s", uid, c.Request().Method, c.Request().URL.Path)
-c.Set("uid", uid)
-return next(c)
+func hasCookie(c echo.Context, name string) bool {
+	cookie, err := c.Cookie(name)
+	return err == nil && cookie.Value != ""
 }
+
+// requiresCSRFCheck reports whether the method can change server state
+func requiresCSRFCheck(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
 }
+
+// validCSRFToken checks the double-submit token: the X-CSRF-Token header must match the CSRF cookie
+func validCSRFToken(c echo.Context) bool {
+	header := c.Request().Header.Get(config.CSRFHeaderName)
+	cookie, err := c.Cookie(config.CSRFCookieName)
+	if header == "" || err != nil || cookie.Value == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) == 1
 }
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/service"
+)
+
+// currentUserKey is the context key of the authenticated user's database record
+const currentUserKey = "user"
+
+// RequireRole allows the request only when the authenticated user has one of the roles.
+// FirebaseAuthMiddleware must run before this middleware.
+func RequireRole(userService *service.UserService, roles ...model.UserRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, err := loadCurrentUser(c, userService)
+			if err != nil {
+				return respondUserLookupError(c, err)
+			}
+
+			if !hasRole(user, roles) {
+				log.Printf("[WARN] Role %s of user %d is not allowed for request: %s %s", user.Role, user.ID, c.Request().Method, c.Request().URL.Path)
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Insufficient permissions"})
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireSelfOrRole allows the request when the user ID in the path parameter is the
+// authenticated user's own ID, or when the user has one of the roles.
+func RequireSelfOrRole(userService *service.UserService, param string, roles ...model.UserRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, err := loadCurrentUser(c, userService)
+			if err != nil {
+				return respondUserLookupError(c, err)
+			}
+
+			if hasRole(user, roles) {
+				return next(c)
+			}
+			if id, err := strconv.ParseUint(c.Param(param), 10, 32); err == nil && uint(id) == user.ID {
+				return next(c)
+			}
+
+			log.Printf("[WARN] User %d is not allowed to access user %s: %s %s", user.ID, c.Param(param), c.Request().Method, c.Request().URL.Path)
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Insufficient permissions"})
+		}
+	}
+}
+
+// CurrentUser returns the user loaded by RequireRole / RequireSelfOrRole
+func CurrentUser(c echo.Context) (*model.User, bool) {
+	user, ok := c.Get(currentUserKey).(*model.User)
+	return user, ok
+}
+
+// loadCurrentUser looks up the authenticated user once per request
+func loadCurrentUser(c echo.Context, userService *service.UserService) (*model.User, error) {
+	if user, ok := CurrentUser(c); ok {
+		return user, nil
+	}
+
+	uid, ok := c.Get("uid").(string)
+	if !ok || uid == "" {
+		return nil, errUnauthenticated
+	}
+
+	user, err := userService.GetUserByFirebaseUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(currentUserKey, user)
+	return user, nil
+}
+
+var errUnauthenticated = errors.New("unauthenticated")
+
+func respondUserLookupError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errUnauthenticated):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	case errors.Is(err, service.ErrUserNotFound):
+		// Firebase にはいるが DB にプロフィールがないユーザーには権限を与えない
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "User profile not found"})
+	}
+	log.Printf("[ERROR] Failed to load user for authorization: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to check permissions"})
+}
+
+func hasRole(user *model.User, roles []model.UserRole) bool {
+	for _, role := range roles {
+		if user.Role == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"strings"
+
+	"zerodelay/internal/domain/model"
+)
+
+// providerErrorCodes maps Firebase Auth REST API error codes to domain error codes.
+// ローカル認証プロバイダーも同じコード名を使うため共通で利用する
+var providerErrorCodes = map[string]model.AuthErrorCode{
+	"EMAIL_EXISTS":                   model.AuthErrEmailExists,
+	"INVALID_EMAIL":                  model.AuthErrInvalidEmail,
+	"MISSING_EMAIL":                  model.AuthErrInvalidEmail,
+	"WEAK_PASSWORD":                  model.AuthErrWeakPassword,
+	"MISSING_PASSWORD":               model.AuthErrWeakPassword,
+	"INVALID_LOGIN_CREDENTIALS":      model.AuthErrInvalidCredentials,
+	"INVALID_PASSWORD":               model.AuthErrInvalidCredentials,
+	"TOO_MANY_ATTEMPTS_TRY_LATER":    model.AuthErrTooManyAttempts,
+	"USER_DISABLED":                  model.AuthErrUserDisabled,
+	"USER_NOT_FOUND":                 model.AuthErrUserNotFound,
+	"EMAIL_NOT_FOUND":                model.AuthErrEmailNotFound,
+	"INVALID_REFRESH_TOKEN":          model.AuthErrInvalidRefreshToken,
+	"MISSING_REFRESH_TOKEN":          model.AuthErrInvalidRefreshToken,
+	"TOKEN_EXPIRED":                  model.AuthErrInvalidRefreshToken,
+	"INVALID_ID_TOKEN":               model.AuthErrInvalidToken,
+	"TOKEN_REVOKED":                  model.AuthErrTokenRevoked,
+	"CREDENTIAL_TOO_OLD_LOGIN_AGAIN": model.AuthErrCredentialTooOld,
+	"INVALID_OOB_CODE":               model.AuthErrInvalidActionCode,
+	"EXPIRED_OOB_CODE":               model.AuthErrInvalidActionCode,
+	"PROVIDER_ALREADY_LINKED":        model.AuthErrNotGuest,
+}
+
+// authErrorFromProvider converts a provider error message such as
+// "WEAK_PASSWORD : Password should be at least 6 characters" to a typed AuthError
+func authErrorFromProvider(message string) *model.AuthError {
+	code := strings.TrimSpace(message)
+	if i := strings.IndexAny(code, " :"); i >= 0 {
+		code = code[:i]
+	}
+	if mapped, ok := providerErrorCodes[code]; ok {
+		return model.NewAuthError(mapped, code)
+	}
+	return model.NewAuthError(model.AuthErrFailed, code)
+}
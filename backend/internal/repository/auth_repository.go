@@ -9,28 +9,52 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	fbauth "firebase.google.com/go/v4/auth"
+	"google.golang.org/api/iterator"
 
+	"zerodelay/internal/config"
 	"zerodelay/internal/domain/model"
 )
 
 const (
-	firebaseAuthBaseURL      = "https://identitytoolkit.googleapis.com/v1"
-	signUpEndpoint           = "/accounts:signUp"
-	signInEndpoint           = "/accounts:signInWithPassword"
-	sendVerificationEndpoint = "/accounts:sendOobCode"
+	firebaseAuthBaseURL   = "https://identitytoolkit.googleapis.com/v1"
+	secureTokenBaseURL    = "https://securetoken.googleapis.com/v1"
+	signUpEndpoint        = "/accounts:signUp"
+	signInEndpoint        = "/accounts:signInWithPassword"
+	sendOobCodeEndpoint   = "/accounts:sendOobCode"
+	updateAccountEndpoint = "/accounts:update"
+	resetPasswordEndpoint = "/accounts:resetPassword"
+	tokenEndpoint         = "/token"
+	emulatorAPIKey        = "fake-api-key"
 )
 
 type authRepository struct {
 	firebaseAuth *fbauth.Client
 	apiKey       string
 	baseURL      string
+	tokenBaseURL string
 	httpClient   *http.Client
 }
 
 func NewAuthRepository(firebaseAuth *fbauth.Client) *authRepository {
 	apiKey := os.Getenv("FIREBASE_API_KEY")
+	baseURL := firebaseAuthBaseURL
+	tokenBaseURL := secureTokenBaseURL
+
+	// Auth Emulator 使用時は REST API の宛先をエミュレーターに切り替える
+	if host := config.AuthEmulatorHost(); host != "" {
+		baseURL = fmt.Sprintf("http://%s/%s", host, strings.TrimPrefix(firebaseAuthBaseURL, "https://"))
+		tokenBaseURL = fmt.Sprintf("http://%s/%s", host, strings.TrimPrefix(secureTokenBaseURL, "https://"))
+		if apiKey == "" {
+			// エミュレーターはAPIキーを検証しないが、key パラメータ自体は必要
+			apiKey = emulatorAPIKey
+		}
+		log.Printf("[INFO] Firebase Auth REST API is routed to emulator at %s", host)
+	}
+
 	if apiKey == "" {
 		log.Println("[WARN] FIREBASE_API_KEY is not set in .env file")
 	}
@@ -38,66 +62,78 @@ func NewAuthRepository(firebaseAuth *fbauth.Client) *authRepository {
 	return &authRepository{
 		firebaseAuth: firebaseAuth,
 		apiKey:       apiKey,
-		baseURL:      firebaseAuthBaseURL,
+		baseURL:      baseURL,
+		tokenBaseURL: tokenBaseURL,
 		httpClient:   &http.Client{},
 	}
 }
 
 func (r *authRepository) callFirebaseAuthAPI(ctx context.Context, endpoint string, payload map[string]string) (*model.AuthResponse, error) {
+	var fbResp model.FirebaseAuthResponse
+	if err := r.postFirebaseAPI(ctx, r.baseURL+endpoint, payload, &fbResp); err != nil {
+		return nil, err
+	}
+
+	// FirebaseAuthResponseをAuthResponseに変換
+	authResp := &model.AuthResponse{
+		IDToken:      fbResp.IDToken,
+		RefreshToken: fbResp.RefreshToken,
+		ExpiresIn:    fbResp.ExpiresIn,
+		Email:        fbResp.Email,
+		LocalID:      fbResp.LocalID,
+		Registered:   fbResp.Registered,
+	}
+
+	return authResp, nil
+}
+
+// postFirebaseAPI posts a JSON payload to a Firebase REST API and decodes the response into out
+func (r *authRepository) postFirebaseAPI(ctx context.Context, endpointURL string, payload map[string]string, out interface{}) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		log.Printf("[ERROR] Failed to marshal request payload: %v", err)
-		return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+		return fmt.Errorf("failed to marshal request payload: %w", err)
 	}
 
-	url := fmt.Sprintf("%s%s?key=%s", r.baseURL, endpoint, r.apiKey)
+	url := fmt.Sprintf("%s?key=%s", endpointURL, r.apiKey)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
 		log.Printf("[ERROR] Failed to create HTTP request: %v", err)
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
 		log.Printf("[ERROR] Failed to communicate with Firebase: %v", err)
-		return nil, fmt.Errorf("failed to communicate with Firebase: %w", err)
+		return fmt.Errorf("failed to communicate with Firebase: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Printf("[ERROR] Failed to read response body: %v", err)
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		var fbErr model.FirebaseError
 		if err := json.Unmarshal(respBody, &fbErr); err != nil {
 			log.Printf("[ERROR] Failed to parse Firebase error response: %v", err)
-			return nil, fmt.Errorf("firebase request failed with status %d", resp.StatusCode)
+			return fmt.Errorf("firebase request failed with status %d", resp.StatusCode)
 		}
 		log.Printf("[ERROR] Firebase API error: %s", fbErr.Error.Message)
-		return nil, fmt.Errorf("firebase error: %s", fbErr.Error.Message)
+		return authErrorFromProvider(fbErr.Error.Message)
 	}
 
-	var fbResp model.FirebaseAuthResponse
-	if err := json.Unmarshal(respBody, &fbResp); err != nil {
-		log.Printf("[ERROR] Failed to parse auth response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if out == nil {
+		return nil
 	}
-
-	// FirebaseAuthResponseをAuthResponseに変換
-	authResp := &model.AuthResponse{
-		IDToken:      fbResp.IDToken,
-		RefreshToken: fbResp.RefreshToken,
-		ExpiresIn:    fbResp.ExpiresIn,
-		Email:        fbResp.Email,
-		LocalID:      fbResp.LocalID,
-		Registered:   fbResp.Registered,
+	if err := json.Unmarshal(respBody, out); err != nil {
+		log.Printf("[ERROR] Failed to parse auth response: %v", err)
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
-
-	return authResp, nil
+	return nil
 }
 
 func (r *authRepository) SignUp(ctx context.Context, req *model.SignUpRequest) (*model.AuthResponse, error) {
@@ -119,6 +155,43 @@ func (r *authRepository) SignUp(ctx context.Context, req *model.SignUpRequest) (
 	return resp, nil
 }
 
+// SignUpAnonymously creates an anonymous Firebase user (requires the Anonymous provider to be enabled)
+func (r *authRepository) SignUpAnonymously(ctx context.Context) (*model.AuthResponse, error) {
+	payload := map[string]string{
+		"returnSecureToken": "true",
+	}
+
+	resp, err := r.callFirebaseAuthAPI(ctx, signUpEndpoint, payload)
+	if err != nil {
+		log.Println("[INFO] Anonymous sign up failed")
+		return nil, err
+	}
+
+	log.Printf("[INFO] Anonymous user signed up: %s", resp.LocalID)
+	return resp, nil
+}
+
+// LinkEmailPassword links an email and password to the anonymous user of the ID token.
+// UID は変わらないため、ゲストとして保存したデータはそのまま引き継がれる
+func (r *authRepository) LinkEmailPassword(ctx context.Context, idToken string, email string, password string) (*model.AuthResponse, error) {
+	payload := map[string]string{
+		"idToken":           idToken,
+		"email":             email,
+		"password":          password,
+		"returnSecureToken": "true",
+	}
+
+	log.Printf("[DEBUG] Attempting to link email to anonymous user: %s", email)
+	resp, err := r.callFirebaseAuthAPI(ctx, updateAccountEndpoint, payload)
+	if err != nil {
+		log.Println("[INFO] Linking email/password failed")
+		return nil, err
+	}
+
+	log.Printf("[INFO] Linked email/password to user: %s", resp.LocalID)
+	return resp, nil
+}
+
 func (r *authRepository) Login(ctx context.Context, req *model.LoginRequest) (*model.AuthResponse, error) {
 	payload := map[string]string{
 		"email":             req.Email,
@@ -138,22 +211,117 @@ func (r *authRepository) Login(ctx context.Context, req *model.LoginRequest) (*m
 	return resp, nil
 }
 
-func (r *authRepository) VerifyIDToken(ctx context.Context, idToken string) (string, error) {
+// RefreshToken exchanges a refresh token for a new ID token through the Secure Token API
+func (r *authRepository) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthResponse, error) {
+	payload := map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+	}
+
+	var tokenResp model.SecureTokenResponse
+	if err := r.postFirebaseAPI(ctx, r.tokenBaseURL+tokenEndpoint, payload, &tokenResp); err != nil {
+		log.Println("[INFO] Token refresh failed")
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] Refreshed ID token for user: %s", tokenResp.UserID)
+	return &model.AuthResponse{
+		IDToken:      tokenResp.IDToken,
+		RefreshToken: tokenResp.RefreshToken,
+		ExpiresIn:    tokenResp.ExpiresIn,
+		LocalID:      tokenResp.UserID,
+	}, nil
+}
+
+func (r *authRepository) VerifyIDToken(ctx context.Context, idToken string) (*model.TokenInfo, error) {
 	token, err := r.firebaseAuth.VerifyIDToken(ctx, idToken)
 	if err != nil {
 		log.Printf("[ERROR] Failed to verify ID token: %v", err)
-		return "", fmt.Errorf("invalid or expired ID token: %w", err)
+		return nil, fmt.Errorf("invalid or expired ID token: %w", err)
 	}
 	log.Println("[INFO] Token verification successful")
 	log.Printf("[DEBUG] Verified token for UID: %s", token.UID)
-	return token.UID, nil
+	return tokenInfoFromFirebase(token), nil
+}
+
+// tokenInfoFromFirebase extracts the claims the API relies on from a verified Firebase token
+func tokenInfoFromFirebase(token *fbauth.Token) *model.TokenInfo {
+	info := &model.TokenInfo{UID: token.UID}
+	if email, ok := token.Claims["email"].(string); ok {
+		info.Email = email
+	}
+	if verified, ok := token.Claims["email_verified"].(bool); ok {
+		info.EmailVerified = verified
+	}
+	if authTime, ok := token.Claims["auth_time"].(float64); ok {
+		info.AuthTime = time.Unix(int64(authTime), 0)
+	}
+	info.Anonymous = token.Firebase.SignInProvider == "anonymous"
+	return info
+}
+
+// CreateSessionCookie exchanges an ID token for a Firebase session cookie
+func (r *authRepository) CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error) {
+	cookie, err := r.firebaseAuth.SessionCookie(ctx, idToken, expiresIn)
+	if err != nil {
+		log.Printf("[ERROR] Failed to create session cookie: %v", err)
+		return "", fmt.Errorf("failed to create session cookie: %w", err)
+	}
+	return cookie, nil
+}
+
+// VerifySessionCookie verifies a Firebase session cookie and returns its claims
+func (r *authRepository) VerifySessionCookie(ctx context.Context, sessionCookie string) (*model.TokenInfo, error) {
+	token, err := r.firebaseAuth.VerifySessionCookie(ctx, sessionCookie)
+	if err != nil {
+		log.Printf("[WARN] Session cookie verification failed: %v", err)
+		return nil, fmt.Errorf("invalid or expired session cookie: %w", err)
+	}
+	return tokenInfoFromFirebase(token), nil
+}
+
+// VerifyIDTokenAndCheckRevoked verifies an ID token and rejects it when the user's sessions were revoked
+func (r *authRepository) VerifyIDTokenAndCheckRevoked(ctx context.Context, idToken string) (*model.TokenInfo, error) {
+	token, err := r.firebaseAuth.VerifyIDTokenAndCheckRevoked(ctx, idToken)
+	if err != nil {
+		log.Printf("[WARN] ID token revocation check failed: %v", err)
+		return nil, fmt.Errorf("invalid, expired or revoked ID token: %w", err)
+	}
+	return tokenInfoFromFirebase(token), nil
+}
+
+// VerifySessionCookieAndCheckRevoked verifies a session cookie and rejects it when the user's sessions were revoked
+func (r *authRepository) VerifySessionCookieAndCheckRevoked(ctx context.Context, sessionCookie string) (*model.TokenInfo, error) {
+	token, err := r.firebaseAuth.VerifySessionCookieAndCheckRevoked(ctx, sessionCookie)
+	if err != nil {
+		log.Printf("[WARN] Session cookie revocation check failed: %v", err)
+		return nil, fmt.Errorf("invalid, expired or revoked session cookie: %w", err)
+	}
+	return tokenInfoFromFirebase(token), nil
+}
+
+// RevokeRefreshTokens invalidates every refresh token and session of the user
+func (r *authRepository) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	if err := r.firebaseAuth.RevokeRefreshTokens(ctx, uid); err != nil {
+		log.Printf("[ERROR] Failed to revoke refresh tokens for %s: %v", uid, err)
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	log.Printf("[INFO] Revoked refresh tokens for user: %s", uid)
+	return nil
 }
 
 func (r *authRepository) UpdateEmail(ctx context.Context, uid string, newEmail string) error {
-	params := (&fbauth.UserToUpdate{}).Email(newEmail)
+	// 新しいアドレスは確認リンクで所有が確認済みのため、確認済みとして設定する
+	params := (&fbauth.UserToUpdate{}).Email(newEmail).EmailVerified(true)
 	_, err := r.firebaseAuth.UpdateUser(ctx, uid, params)
 	if err != nil {
 		log.Printf("[ERROR] Failed to update email in Firebase: %v", err)
+		switch {
+		case fbauth.IsEmailAlreadyExists(err):
+			return model.NewAuthError(model.AuthErrEmailExists, "EMAIL_EXISTS")
+		case fbauth.IsInvalidEmail(err):
+			return model.NewAuthError(model.AuthErrInvalidEmail, "INVALID_EMAIL")
+		}
 		return fmt.Errorf("failed to update email in Firebase: %w", err)
 	}
 	log.Printf("[INFO] Email updated successfully in Firebase for UID: %s", uid)
@@ -162,6 +330,11 @@ func (r *authRepository) UpdateEmail(ctx context.Context, uid string, newEmail s
 
 func (r *authRepository) DeleteUser(ctx context.Context, uid string) error {
 	if err := r.firebaseAuth.DeleteUser(ctx, uid); err != nil {
+		// 既に削除済みなら成功として扱う（再試行を安全にするため）
+		if fbauth.IsUserNotFound(err) {
+			log.Printf("[INFO] Firebase user %s already deleted", uid)
+			return nil
+		}
 		log.Printf("[ERROR] Failed to delete Firebase user %s: %v", uid, err)
 		return fmt.Errorf("failed to delete Firebase user: %w", err)
 	}
@@ -175,47 +348,103 @@ func (r *authRepository) SendEmailVerification(ctx context.Context, idToken stri
 		"idToken":     idToken,
 	}
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		log.Printf("[ERROR] Failed to marshal email verification request: %v", err)
-		return fmt.Errorf("failed to marshal request payload: %w", err)
+	if err := r.sendOobCode(ctx, payload); err != nil {
+		log.Printf("[ERROR] Failed to send email verification: %v", err)
+		return fmt.Errorf("failed to send email verification: %w", err)
 	}
 
-	url := fmt.Sprintf("%s%s?key=%s", r.baseURL, sendVerificationEndpoint, r.apiKey)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
-	if err != nil {
-		log.Printf("[ERROR] Failed to create HTTP request: %v", err)
-		return fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
+	log.Println("[INFO] Email verification sent successfully")
+	return nil
+}
 
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		log.Printf("[ERROR] Failed to send email verification: %v", err)
-		return fmt.Errorf("failed to send email verification: %w", err)
+// SendPasswordResetEmail sends the PASSWORD_RESET email to the address
+func (r *authRepository) SendPasswordResetEmail(ctx context.Context, email string) error {
+	payload := map[string]string{
+		"requestType": "PASSWORD_RESET",
+		"email":       email,
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("[ERROR] Failed to read email verification response: %v", err)
-		return fmt.Errorf("failed to read email verification response: %w", err)
+	if err := r.sendOobCode(ctx, payload); err != nil {
+		log.Printf("[ERROR] Failed to send password reset email: %v", err)
+		return fmt.Errorf("failed to send password reset email: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[ERROR] Email verification failed with status %d: %s", resp.StatusCode, string(respBody))
-		return fmt.Errorf("failed to send email verification: status %d", resp.StatusCode)
+	log.Printf("[INFO] Password reset email sent to: %s", email)
+	return nil
+}
+
+// ApplyEmailVerification marks the email as verified with the code from a verification email
+func (r *authRepository) ApplyEmailVerification(ctx context.Context, oobCode string) error {
+	payload := map[string]string{"oobCode": oobCode}
+	if err := r.postFirebaseAPI(ctx, r.baseURL+updateAccountEndpoint, payload, nil); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
 	}
+	log.Println("[INFO] Email verified with action code")
+	return nil
+}
 
-	log.Printf("[INFO] Email verification sent successfully. Response: %s", string(respBody))
+// ConfirmPasswordReset sets a new password with the code from a password reset email
+func (r *authRepository) ConfirmPasswordReset(ctx context.Context, oobCode string, newPassword string) error {
+	payload := map[string]string{
+		"oobCode":     oobCode,
+		"newPassword": newPassword,
+	}
+	if err := r.postFirebaseAPI(ctx, r.baseURL+resetPasswordEndpoint, payload, nil); err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+	log.Println("[INFO] Password reset with action code")
 	return nil
 }
 
-func (r *authRepository) GetUser(ctx context.Context, uid string) (*fbauth.UserRecord, error) {
+// sendOobCode asks Firebase to send an out-of-band email (verification, password reset, ...)
+func (r *authRepository) sendOobCode(ctx context.Context, payload map[string]string) error {
+	return r.postFirebaseAPI(ctx, r.baseURL+sendOobCodeEndpoint, payload, nil)
+}
+
+func (r *authRepository) GetUser(ctx context.Context, uid string) (*model.AuthUser, error) {
 	user, err := r.firebaseAuth.GetUser(ctx, uid)
 	if err != nil {
 		log.Printf("[ERROR] Failed to get user from Firebase: %v", err)
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	return user, nil
+	return &model.AuthUser{
+		UID:           user.UID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Disabled:      user.Disabled,
+		Anonymous:     isAnonymousFirebaseUser(user),
+	}, nil
+}
+
+// ListUsers pages through all Firebase users (1000 per request)
+func (r *authRepository) ListUsers(ctx context.Context) ([]model.AuthUser, error) {
+	var users []model.AuthUser
+	iter := r.firebaseAuth.Users(ctx, "")
+	for {
+		user, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			log.Printf("[ERROR] Failed to list Firebase users: %v", err)
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+		authUser := model.AuthUser{
+			UID:           user.UID,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Disabled:      user.Disabled,
+			Anonymous:     isAnonymousFirebaseUser(user.UserRecord),
+		}
+		if user.UserMetadata != nil {
+			authUser.CreatedAt = time.UnixMilli(user.UserMetadata.CreationTimestamp)
+		}
+		users = append(users, authUser)
+	}
+	return users, nil
+}
+
+// isAnonymousFirebaseUser reports whether the user has no linked sign-in provider (anonymous sign-in)
+func isAnonymousFirebaseUser(user *fbauth.UserRecord) bool {
+	return len(user.ProviderUserInfo) == 0
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+)
+
+// memoryAuthThrottleRepository keeps throttle state in process memory.
+// 再起動でリセットされ、インスタンス間で共有されない
+type memoryAuthThrottleRepository struct {
+	mu      sync.Mutex
+	entries map[string]*model.AuthThrottle
+}
+
+// NewMemoryAuthThrottleRepository creates the in-memory throttle store
+func NewMemoryAuthThrottleRepository() repository.AuthThrottleRepository {
+	return &memoryAuthThrottleRepository{entries: make(map[string]*model.AuthThrottle)}
+}
+
+func (r *memoryAuthThrottleRepository) entry(key string, now time.Time) *model.AuthThrottle {
+	entry, ok := r.entries[key]
+	if !ok {
+		entry = &model.AuthThrottle{Key: key, WindowStart: now}
+		r.entries[key] = entry
+	}
+	entry.UpdatedAt = now
+	return entry
+}
+
+func (r *memoryAuthThrottleRepository) Hit(ctx context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(key, now)
+	if !entry.WindowStart.After(now.Add(-window)) {
+		entry.Attempts = 0
+		entry.WindowStart = now
+	}
+	entry.Attempts++
+	return entry.Attempts, entry.WindowStart, nil
+}
+
+func (r *memoryAuthThrottleRepository) RecordFailure(ctx context.Context, key string, resetAfter time.Duration, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(key, now)
+	if entry.LastFailureAt == nil || entry.LastFailureAt.Before(now.Add(-resetAfter)) {
+		entry.Failures = 0
+	}
+	entry.Failures++
+	entry.LastFailureAt = &now
+	return entry.Failures, nil
+}
+
+func (r *memoryAuthThrottleRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(key, time.Now())
+	entry.LockedUntil = &until
+	return nil
+}
+
+func (r *memoryAuthThrottleRepository) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if !ok || entry.LockedUntil == nil || !entry.LockedUntil.After(now) {
+		return time.Time{}, nil
+	}
+	return *entry.LockedUntil, nil
+}
+
+func (r *memoryAuthThrottleRepository) ResetFailures(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.entries[key]; ok {
+		entry.Failures = 0
+		entry.LastFailureAt = nil
+		entry.LockedUntil = nil
+	}
+	return nil
+}
+
+func (r *memoryAuthThrottleRepository) DeleteStale(ctx context.Context, before time.Time, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, entry := range r.entries {
+		if entry.UpdatedAt.Before(before) && (entry.LockedUntil == nil || !entry.LockedUntil.After(now)) {
+			delete(r.entries, key)
+		}
+	}
+	return nil
+}
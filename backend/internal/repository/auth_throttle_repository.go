@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+)
+
+// authThrottleRepository keeps throttle state in PostgreSQL so that every instance shares it.
+// カウンターの更新は INSERT ... ON CONFLICT の1文で行い、同時リクエストでも取りこぼさない
+type authThrottleRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthThrottleRepository creates the PostgreSQL-backed throttle store
+func NewAuthThrottleRepository(db *gorm.DB) repository.AuthThrottleRepository {
+	return &authThrottleRepository{db: db}
+}
+
+func (r *authThrottleRepository) Hit(ctx context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error) {
+	var row struct {
+		Attempts    int
+		WindowStart time.Time
+	}
+	windowFloor := now.Add(-window)
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO auth_throttles (key, attempts, window_start, failures, updated_at)
+		VALUES (@key, 1, @now, 0, @now)
+		ON CONFLICT (key) DO UPDATE SET
+			attempts = CASE WHEN auth_throttles.window_start <= @floor THEN 1 ELSE auth_throttles.attempts + 1 END,
+			window_start = CASE WHEN auth_throttles.window_start <= @floor THEN @now ELSE auth_throttles.window_start END,
+			updated_at = @now
+		RETURNING attempts, window_start`,
+		map[string]interface{}{"key": key, "now": now, "floor": windowFloor},
+	).Scan(&row).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return row.Attempts, row.WindowStart, nil
+}
+
+func (r *authThrottleRepository) RecordFailure(ctx context.Context, key string, resetAfter time.Duration, now time.Time) (int, error) {
+	var failures int
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO auth_throttles (key, attempts, window_start, failures, last_failure_at, updated_at)
+		VALUES (@key, 0, @now, 1, @now, @now)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN auth_throttles.last_failure_at IS NULL OR auth_throttles.last_failure_at < @floor THEN 1
+				ELSE auth_throttles.failures + 1
+			END,
+			last_failure_at = @now,
+			updated_at = @now
+		RETURNING failures`,
+		map[string]interface{}{"key": key, "now": now, "floor": now.Add(-resetAfter)},
+	).Scan(&failures).Error
+	if err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+func (r *authThrottleRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.AuthThrottle{}).
+		Where("key = ?", key).
+		Updates(map[string]interface{}{"locked_until": until, "updated_at": time.Now()}).Error
+}
+
+func (r *authThrottleRepository) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error) {
+	var throttle model.AuthThrottle
+	err := r.db.WithContext(ctx).Select("locked_until").Where("key = ?", key).Take(&throttle).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if throttle.LockedUntil == nil || !throttle.LockedUntil.After(now) {
+		return time.Time{}, nil
+	}
+	return *throttle.LockedUntil, nil
+}
+
+func (r *authThrottleRepository) ResetFailures(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Model(&model.AuthThrottle{}).
+		Where("key = ?", key).
+		Updates(map[string]interface{}{"failures": 0, "last_failure_at": nil, "locked_until": nil}).Error
+}
+
+func (r *authThrottleRepository) DeleteStale(ctx context.Context, before time.Time, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("updated_at < ?", before).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Delete(&model.AuthThrottle{}).Error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+)
+
+type emailChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailChangeRepository creates a new email change repository
+func NewEmailChangeRepository(db *gorm.DB) repository.EmailChangeRepository {
+	return &emailChangeRepository{db: db}
+}
+
+func (r *emailChangeRepository) Replace(change *model.EmailChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", change.UserID).Delete(&model.EmailChange{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+}
+
+func (r *emailChangeRepository) FindByTokenHash(tokenHash string) (*model.EmailChange, error) {
+	var change model.EmailChange
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&change).Error; err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *emailChangeRepository) FindByUserID(userID uint) (*model.EmailChange, error) {
+	var change model.EmailChange
+	if err := r.db.Where("user_id = ?", userID).First(&change).Error; err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *emailChangeRepository) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.EmailChange{}).Error
+}
+
+func (r *emailChangeRepository) Apply(change *model.EmailChange, fn func() error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", change.UserID).Update("email", change.NewEmail).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.EmailChange{}, change.ID).Error; err != nil {
+			return err
+		}
+		return fn()
+	})
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+)
+
+type hazardRepository struct {
+	db *gorm.DB
+}
+
+// NewHazardRepository creates a new hazard repository
+func NewHazardRepository(db *gorm.DB) repository.HazardRepository {
+	return &hazardRepository{db: db}
+}
+
+// ReplaceBySource deletes the zones of a source and inserts the new ones in one transaction.
+// It returns the number of deleted zones.
+func (r *hazardRepository) ReplaceBySource(source string, zones []*model.HazardZone) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("source = ?", source).Delete(&model.HazardZone{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		if len(zones) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(zones, 200).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (r *hazardRepository) CountBySource(source string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.HazardZone{}).Where("source = ?", source).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindByBBoxContaining returns zones whose bounding box contains the point.
+// 厳密な内外判定はサービス層で行う
+func (r *hazardRepository) FindByBBoxContaining(lat, lon float64) ([]model.HazardZone, error) {
+	var zones []model.HazardZone
+	err := r.db.Where("min_lat <= ? AND max_lat >= ?", lat, lat).
+		Where("min_lon <= ? AND max_lon >= ?", lon, lon).
+		Find(&zones).Error
+	if err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
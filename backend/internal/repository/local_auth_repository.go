@@ -0,0 +1,627 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/mail"
+)
+
+// Token purposes of the local provider. 用途ごとに別のトークンとして扱い、取り違えを防ぐ
+const (
+	localPurposeID            = "id"
+	localPurposeRefresh       = "refresh"
+	localPurposeSession       = "session"
+	localPurposeVerifyEmail   = "verify_email"
+	localPurposeResetPassword = "reset_password"
+)
+
+// Token lifetimes, matching Firebase where it has an equivalent
+const (
+	localIDTokenTTL       = time.Hour
+	localRefreshTokenTTL  = 30 * 24 * time.Hour
+	localVerifyEmailTTL   = 24 * time.Hour
+	localResetPasswordTTL = time.Hour
+	localRecentSignIn     = 5 * time.Minute // セッションクッキー発行に必要な直近ログイン
+	localMinPasswordLen   = 6
+	localTokenIssuer      = "zerodelay-local-auth"
+)
+
+// Error codes use the same names as the Firebase REST API so callers can handle both providers alike
+const (
+	localErrEmailExists        = "EMAIL_EXISTS"
+	localErrEmailNotFound      = "EMAIL_NOT_FOUND"
+	localErrInvalidCredentials = "INVALID_LOGIN_CREDENTIALS"
+	localErrUserDisabled       = "USER_DISABLED"
+	localErrUserNotFound       = "USER_NOT_FOUND"
+	localErrInvalidRefresh     = "INVALID_REFRESH_TOKEN"
+	localErrTokenRevoked       = "TOKEN_REVOKED"
+	localErrInvalidOobCode     = "INVALID_OOB_CODE"
+	localErrWeakPassword       = "WEAK_PASSWORD : Password should be at least 6 characters"
+	localErrMissingEmail       = "MISSING_EMAIL"
+	localErrRecentSignIn       = "CREDENTIAL_TOO_OLD_LOGIN_AGAIN"
+	localErrInvalidIDToken     = "INVALID_ID_TOKEN"
+	localErrAlreadyLinked      = "PROVIDER_ALREADY_LINKED"
+)
+
+type localClaims struct {
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified"`
+	AuthTime      int64  `json:"auth_time,omitempty"`
+	Anonymous     bool   `json:"anonymous,omitempty"`
+	Purpose       string `json:"purpose"`
+	// PasswordFingerprint ties password reset codes to the current password so they are single-use
+	PasswordFingerprint string `json:"pwf,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// localAuthRepository implements AuthRepository without Firebase.
+// パスワードは bcrypt でハッシュ化して PostgreSQL に保存し、HS256 で署名した独自の JWT を発行する。
+type localAuthRepository struct {
+	db        *gorm.DB
+	secret    []byte
+	mailer    mail.Mailer
+	actionURL string
+}
+
+// NewLocalAuthRepository creates the local auth provider
+func NewLocalAuthRepository(db *gorm.DB, secret string, mailer mail.Mailer, actionURL string) *localAuthRepository {
+	return &localAuthRepository{
+		db:        db,
+		secret:    []byte(secret),
+		mailer:    mailer,
+		actionURL: actionURL,
+	}
+}
+
+func (r *localAuthRepository) SignUp(ctx context.Context, req *model.SignUpRequest) (*model.AuthResponse, error) {
+	email := normalizeLocalEmail(req.Email)
+	if email == "" {
+		return nil, localAuthError(localErrMissingEmail)
+	}
+	if len(req.Password) < localMinPasswordLen {
+		return nil, localAuthError(localErrWeakPassword)
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.LocalAuthUser{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	}
+	if count > 0 {
+		return nil, localAuthError(localErrEmailExists)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	uid, err := newLocalUID()
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.LocalAuthUser{
+		UID:              uid,
+		Email:            email,
+		PasswordHash:     string(hash),
+		TokensValidAfter: time.Now().Add(-time.Second),
+	}
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+		return nil, fmt.Errorf("failed to create local auth user: %w", err)
+	}
+
+	log.Printf("[INFO] Local auth user created: %s", uid)
+	return r.issueTokens(user, time.Now())
+}
+
+// SignUpAnonymously creates a guest user without email or password
+func (r *localAuthRepository) SignUpAnonymously(ctx context.Context) (*model.AuthResponse, error) {
+	uid, err := newLocalUID()
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.LocalAuthUser{
+		UID:              uid,
+		Anonymous:        true,
+		TokensValidAfter: time.Now().Add(-time.Second),
+	}
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+		return nil, fmt.Errorf("failed to create local auth user: %w", err)
+	}
+
+	log.Printf("[INFO] Local anonymous user created: %s", uid)
+	return r.issueTokens(user, time.Now())
+}
+
+// LinkEmailPassword turns the anonymous user of the ID token into an email/password user, keeping its UID
+func (r *localAuthRepository) LinkEmailPassword(ctx context.Context, idToken string, email string, password string) (*model.AuthResponse, error) {
+	claims, err := r.parseToken(idToken, localPurposeID)
+	if err != nil {
+		return nil, localAuthError(localErrInvalidIDToken)
+	}
+	user, err := r.findByUID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, localAuthError(localErrUserNotFound)
+		}
+		return nil, err
+	}
+	if isRevoked(claims, user) {
+		return nil, localAuthError(localErrTokenRevoked)
+	}
+	if user.Disabled {
+		return nil, localAuthError(localErrUserDisabled)
+	}
+	if !user.Anonymous {
+		return nil, localAuthError(localErrAlreadyLinked)
+	}
+
+	email = normalizeLocalEmail(email)
+	if email == "" {
+		return nil, localAuthError(localErrMissingEmail)
+	}
+	if len(password) < localMinPasswordLen {
+		return nil, localAuthError(localErrWeakPassword)
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.LocalAuthUser{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	}
+	if count > 0 {
+		return nil, localAuthError(localErrEmailExists)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.Email = email
+	user.PasswordHash = string(hash)
+	user.Anonymous = false
+	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
+		return nil, fmt.Errorf("failed to link email/password: %w", err)
+	}
+
+	log.Printf("[INFO] Linked email/password to local user: %s", user.UID)
+	return r.issueTokens(user, time.Now())
+}
+
+func (r *localAuthRepository) Login(ctx context.Context, req *model.LoginRequest) (*model.AuthResponse, error) {
+	user, err := r.findByEmail(ctx, normalizeLocalEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, localAuthError(localErrInvalidCredentials)
+		}
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		return nil, localAuthError(localErrInvalidCredentials)
+	}
+	if user.Disabled {
+		return nil, localAuthError(localErrUserDisabled)
+	}
+
+	resp, err := r.issueTokens(user, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	resp.Registered = true
+	return resp, nil
+}
+
+func (r *localAuthRepository) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthResponse, error) {
+	claims, err := r.parseToken(refreshToken, localPurposeRefresh)
+	if err != nil {
+		return nil, localAuthError(localErrInvalidRefresh)
+	}
+
+	user, err := r.findByUID(ctx, claims.Subject)
+	if err != nil {
+		return nil, localAuthError(localErrInvalidRefresh)
+	}
+	if user.Disabled {
+		return nil, localAuthError(localErrUserDisabled)
+	}
+	if isRevoked(claims, user) {
+		return nil, localAuthError(localErrInvalidRefresh)
+	}
+
+	return r.issueTokens(user, time.Unix(claims.AuthTime, 0))
+}
+
+func (r *localAuthRepository) VerifyIDToken(ctx context.Context, idToken string) (*model.TokenInfo, error) {
+	claims, err := r.parseToken(idToken, localPurposeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired ID token: %w", err)
+	}
+	return tokenInfoFromLocalClaims(claims), nil
+}
+
+func (r *localAuthRepository) CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error) {
+	claims, err := r.parseToken(idToken, localPurposeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create session cookie: %w", err)
+	}
+	// Firebase と同様、直近にログインしたIDトークンからのみ発行する
+	if time.Since(time.Unix(claims.AuthTime, 0)) > localRecentSignIn {
+		return "", fmt.Errorf("failed to create session cookie: %w", localAuthError(localErrRecentSignIn))
+	}
+
+	session := *claims
+	session.Purpose = localPurposeSession
+	session.IssuedAt = jwt.NewNumericDate(time.Now())
+	session.ExpiresAt = jwt.NewNumericDate(time.Now().Add(expiresIn))
+	return r.signToken(&session)
+}
+
+func (r *localAuthRepository) VerifySessionCookie(ctx context.Context, sessionCookie string) (*model.TokenInfo, error) {
+	claims, err := r.parseToken(sessionCookie, localPurposeSession)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired session cookie: %w", err)
+	}
+	return tokenInfoFromLocalClaims(claims), nil
+}
+
+func (r *localAuthRepository) VerifyIDTokenAndCheckRevoked(ctx context.Context, idToken string) (*model.TokenInfo, error) {
+	return r.verifyAndCheckRevoked(ctx, idToken, localPurposeID)
+}
+
+func (r *localAuthRepository) VerifySessionCookieAndCheckRevoked(ctx context.Context, sessionCookie string) (*model.TokenInfo, error) {
+	return r.verifyAndCheckRevoked(ctx, sessionCookie, localPurposeSession)
+}
+
+func (r *localAuthRepository) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	result := r.db.WithContext(ctx).Model(&model.LocalAuthUser{}).Where("uid = ?", uid).
+		Update("tokens_valid_after", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return localAuthError(localErrUserNotFound)
+	}
+	log.Printf("[INFO] Revoked refresh tokens for local user: %s", uid)
+	return nil
+}
+
+func (r *localAuthRepository) UpdateEmail(ctx context.Context, uid string, newEmail string) error {
+	email := normalizeLocalEmail(newEmail)
+	if email == "" {
+		return localAuthError(localErrMissingEmail)
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.LocalAuthUser{}).Where("email = ? AND uid <> ?", email, uid).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check existing user: %w", err)
+	}
+	if count > 0 {
+		return localAuthError(localErrEmailExists)
+	}
+
+	// 新しいアドレスは確認リンクで所有が確認済みのため、確認済みとして設定する（Firebase 実装と同じ）
+	result := r.db.WithContext(ctx).Model(&model.LocalAuthUser{}).Where("uid = ?", uid).
+		Updates(map[string]interface{}{"email": email, "email_verified": true})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update email: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return localAuthError(localErrUserNotFound)
+	}
+	return nil
+}
+
+func (r *localAuthRepository) DeleteUser(ctx context.Context, uid string) error {
+	// 既に削除済みでも成功として扱う（Firebase 実装と同じ）
+	if err := r.db.WithContext(ctx).Where("uid = ?", uid).Delete(&model.LocalAuthUser{}).Error; err != nil {
+		return fmt.Errorf("failed to delete local auth user: %w", err)
+	}
+	log.Printf("[INFO] Deleted local auth user: %s", uid)
+	return nil
+}
+
+func (r *localAuthRepository) SendEmailVerification(ctx context.Context, idToken string) error {
+	claims, err := r.parseToken(idToken, localPurposeID)
+	if err != nil {
+		return fmt.Errorf("failed to send email verification: %w", err)
+	}
+	user, err := r.findByUID(ctx, claims.Subject)
+	if err != nil {
+		return fmt.Errorf("failed to send email verification: %w", err)
+	}
+
+	code, err := r.signToken(&localClaims{
+		Email:            user.Email,
+		Purpose:          localPurposeVerifyEmail,
+		RegisteredClaims: r.registeredClaims(user.UID, localVerifyEmailTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "【ZeroDelay】メールアドレスの確認",
+		Body: "以下のリンクからメールアドレスを確認してください。\n\n" +
+			r.actionLink("verifyEmail", code) + "\n\n" +
+			"確認コード: " + code,
+	})
+}
+
+func (r *localAuthRepository) SendPasswordResetEmail(ctx context.Context, email string) error {
+	user, err := r.findByEmail(ctx, normalizeLocalEmail(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return localAuthError(localErrEmailNotFound)
+		}
+		return err
+	}
+
+	code, err := r.signToken(&localClaims{
+		Email:               user.Email,
+		Purpose:             localPurposeResetPassword,
+		PasswordFingerprint: passwordFingerprint(user.PasswordHash),
+		RegisteredClaims:    r.registeredClaims(user.UID, localResetPasswordTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "【ZeroDelay】パスワードの再設定",
+		Body: "以下のコードで新しいパスワードを設定してください（有効期限1時間）。\n" +
+			"POST /api/v1/auth/password-reset/confirm に oobCode と newPassword を送信します。\n\n" +
+			r.actionLink("resetPassword", code) + "\n\n" +
+			"確認コード: " + code,
+	})
+}
+
+func (r *localAuthRepository) ApplyEmailVerification(ctx context.Context, oobCode string) error {
+	claims, err := r.parseToken(oobCode, localPurposeVerifyEmail)
+	if err != nil {
+		return localAuthError(localErrInvalidOobCode)
+	}
+
+	// メールアドレス変更後は古い確認コードを無効にする
+	result := r.db.WithContext(ctx).Model(&model.LocalAuthUser{}).
+		Where("uid = ? AND email = ?", claims.Subject, claims.Email).
+		Update("email_verified", true)
+	if result.Error != nil {
+		return fmt.Errorf("failed to verify email: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return localAuthError(localErrInvalidOobCode)
+	}
+	log.Printf("[INFO] Local user email verified: %s", claims.Subject)
+	return nil
+}
+
+func (r *localAuthRepository) ConfirmPasswordReset(ctx context.Context, oobCode string, newPassword string) error {
+	claims, err := r.parseToken(oobCode, localPurposeResetPassword)
+	if err != nil {
+		return localAuthError(localErrInvalidOobCode)
+	}
+	if len(newPassword) < localMinPasswordLen {
+		return localAuthError(localErrWeakPassword)
+	}
+
+	user, err := r.findByUID(ctx, claims.Subject)
+	if err != nil || passwordFingerprint(user.PasswordHash) != claims.PasswordFingerprint {
+		return localAuthError(localErrInvalidOobCode)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// パスワード変更時は既存セッションも失効させる（Firebase と同じ挙動）
+	// メールのリンクを開けた時点でメールアドレスの所有も確認できている
+	err = r.db.WithContext(ctx).Model(user).Updates(map[string]interface{}{
+		"password_hash":      string(hash),
+		"email_verified":     true,
+		"tokens_valid_after": time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+	log.Printf("[INFO] Local user password reset: %s", user.UID)
+	return nil
+}
+
+func (r *localAuthRepository) GetUser(ctx context.Context, uid string) (*model.AuthUser, error) {
+	user, err := r.findByUID(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &model.AuthUser{
+		UID:           user.UID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Disabled:      user.Disabled,
+		Anonymous:     user.Anonymous,
+	}, nil
+}
+
+func (r *localAuthRepository) ListUsers(ctx context.Context) ([]model.AuthUser, error) {
+	var localUsers []model.LocalAuthUser
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&localUsers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	users := make([]model.AuthUser, 0, len(localUsers))
+	for _, user := range localUsers {
+		users = append(users, model.AuthUser{
+			UID:           user.UID,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Disabled:      user.Disabled,
+			Anonymous:     user.Anonymous,
+			CreatedAt:     user.CreatedAt,
+		})
+	}
+	return users, nil
+}
+
+func (r *localAuthRepository) verifyAndCheckRevoked(ctx context.Context, token string, purpose string) (*model.TokenInfo, error) {
+	claims, err := r.parseToken(token, purpose)
+	if err != nil {
+		return nil, fmt.Errorf("invalid, expired or revoked token: %w", err)
+	}
+	user, err := r.findByUID(ctx, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid, expired or revoked token: %w", err)
+	}
+	if user.Disabled {
+		return nil, localAuthError(localErrUserDisabled)
+	}
+	if isRevoked(claims, user) {
+		return nil, localAuthError(localErrTokenRevoked)
+	}
+	return tokenInfoFromLocalClaims(claims), nil
+}
+
+// issueTokens creates an ID token and refresh token pair for the user
+func (r *localAuthRepository) issueTokens(user *model.LocalAuthUser, authTime time.Time) (*model.AuthResponse, error) {
+	idToken, err := r.signToken(&localClaims{
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerified,
+		AuthTime:         authTime.Unix(),
+		Anonymous:        user.Anonymous,
+		Purpose:          localPurposeID,
+		RegisteredClaims: r.registeredClaims(user.UID, localIDTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := r.signToken(&localClaims{
+		AuthTime:         authTime.Unix(),
+		Purpose:          localPurposeRefresh,
+		RegisteredClaims: r.registeredClaims(user.UID, localRefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthResponse{
+		IDToken:      idToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    strconv.Itoa(int(localIDTokenTTL.Seconds())),
+		Email:        user.Email,
+		LocalID:      user.UID,
+	}, nil
+}
+
+func (r *localAuthRepository) registeredClaims(uid string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Issuer:    localTokenIssuer,
+		Subject:   uid,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+}
+
+func (r *localAuthRepository) signToken(claims *localClaims) (string, error) {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(r.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// parseToken validates the signature, expiry, issuer and purpose of a token
+func (r *localAuthRepository) parseToken(token string, purpose string) (*localClaims, error) {
+	claims := &localClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return r.secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claims.Issuer != localTokenIssuer || claims.Purpose != purpose || claims.Subject == "" {
+		return nil, errors.New("token is not valid for this purpose")
+	}
+	return claims, nil
+}
+
+func (r *localAuthRepository) actionLink(mode string, code string) string {
+	return fmt.Sprintf("%s?mode=%s&oobCode=%s", r.actionURL, mode, url.QueryEscape(code))
+}
+
+func (r *localAuthRepository) findByUID(ctx context.Context, uid string) (*model.LocalAuthUser, error) {
+	var user model.LocalAuthUser
+	if err := r.db.WithContext(ctx).Where("uid = ?", uid).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *localAuthRepository) findByEmail(ctx context.Context, email string) (*model.LocalAuthUser, error) {
+	// ゲストはメールアドレスが空のため、空文字で検索されても一致させない
+	if email == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var user model.LocalAuthUser
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// isRevoked reports whether the token was issued before the user's sessions were revoked
+func isRevoked(claims *localClaims, user *model.LocalAuthUser) bool {
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return claims.IssuedAt.Time.Before(user.TokensValidAfter.Truncate(time.Second))
+}
+
+func tokenInfoFromLocalClaims(claims *localClaims) *model.TokenInfo {
+	return &model.TokenInfo{
+		UID:           claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		AuthTime:      time.Unix(claims.AuthTime, 0),
+		Anonymous:     claims.Anonymous,
+	}
+}
+
+func passwordFingerprint(hash string) string {
+	sum := sha256.Sum256([]byte(hash))
+	return hex.EncodeToString(sum[:8])
+}
+
+func normalizeLocalEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// newLocalUID generates a random 28-character UID (the same length as Firebase UIDs)
+func newLocalUID() (string, error) {
+	b := make([]byte, 14)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate UID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func localAuthError(code string) error {
+	return authErrorFromProvider(code)
+}
@@ -1,10 +1,21 @@
 package repository
 
 import (
+	"fmt"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"zerodelay/internal/domain/model"
 	"zerodelay/internal/domain/repository"
+	"zerodelay/internal/geo"
+)
+
+// haversineSQL computes the distance in meters from (?, ?) to place.lat/lon.
+// Placeholders: query lat, query lat, query lon.
+var haversineSQL = fmt.Sprintf(
+	"2 * %f * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(lat - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(lat)) * POWER(SIN(RADIANS(lon - ?) / 2), 2))))",
+	geo.EarthRadiusMeters,
 )
 
 type placeRepository struct {
@@ -36,10 +47,148 @@ func (r *placeRepository) FindAll() ([]model.Place, error) {
 	return places, nil
 }
 
+func (r *placeRepository) FindByFilter(filter model.PlaceFilter) ([]model.Place, error) {
+	var places []model.Place
+	if err := applyPlaceFilter(r.db, filter).Order("id").Find(&places).Error; err != nil {
+		return nil, err
+	}
+	return places, nil
+}
+
+func (r *placeRepository) FindByExternalIDs(externalIDs []string) ([]model.Place, error) {
+	var places []model.Place
+	if len(externalIDs) == 0 {
+		return places, nil
+	}
+	if err := r.db.Where("external_id IN ?", externalIDs).Find(&places).Error; err != nil {
+		return nil, err
+	}
+	return places, nil
+}
+
+func (r *placeRepository) FindNearby(query model.NearbyQuery) ([]model.PlaceWithDistance, error) {
+	// (lat, lon) インデックスで候補を絞り込んでから正確な距離で判定する
+	minLat, minLon, maxLat, maxLon := geo.Around(query.Lat, query.Lon, query.RadiusMeters)
+
+	candidates := r.db.Model(&model.Place{}).
+		Select("place.*, "+haversineSQL+" AS distance_meters", query.Lat, query.Lat, query.Lon).
+		Where("lat BETWEEN ? AND ?", minLat, maxLat)
+	candidates = whereLonBetween(candidates, minLon, maxLon)
+	candidates = applyPlaceFilter(candidates, query.Filter)
+
+	var places []model.PlaceWithDistance
+	err := r.db.Table("(?) AS candidates", candidates).
+		Where("distance_meters <= ?", query.RadiusMeters).
+		Order("distance_meters").
+		Limit(query.Limit).
+		Scan(&places).Error
+	if err != nil {
+		return nil, err
+	}
+	return places, nil
+}
+
+// whereLonBetween limits lon to [minLon, maxLon]. minLon > maxLon is a range crossing the antimeridian (±180°),
+// which is searched as the two ranges on either side
+func whereLonBetween(db *gorm.DB, minLon, maxLon float64) *gorm.DB {
+	if minLon <= maxLon {
+		return db.Where("lon BETWEEN ? AND ?", minLon, maxLon)
+	}
+	return db.Where("(lon >= ? OR lon <= ?)", minLon, maxLon)
+}
+
+// applyPlaceFilter adds the WHERE clauses for the given filter
+func applyPlaceFilter(db *gorm.DB, filter model.PlaceFilter) *gorm.DB {
+	if filter.BBox != nil {
+		db = db.Where("lat BETWEEN ? AND ?", filter.BBox.MinLat, filter.BBox.MaxLat)
+		db = whereLonBetween(db, filter.BBox.MinLon, filter.BBox.MaxLon)
+	}
+	if len(filter.Categories) > 0 {
+		db = db.Where("category IN ?", filter.Categories)
+	}
+	for _, t := range filter.DisasterTypes {
+		// DisasterType はサービス層で検証済みのため列名に直接使用できる
+		db = db.Where(fmt.Sprintf("safe_for_%s = ?", t), true)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("status IN ?", filter.Statuses)
+	}
+	if filter.HasVacancy {
+		db = db.Where("status = ? AND capacity > occupancy", model.PlaceStatusOpen)
+	}
+	return db
+}
+
 func (r *placeRepository) Update(place *model.Place) error {
 	return r.db.Save(place).Error
 }
 
+// UpdateOccupancy reads the place with SELECT ... FOR UPDATE so that concurrent updates of the same
+// shelter are applied one after another, then saves the live status columns and the history entry
+func (r *placeRepository) UpdateOccupancy(id uint, apply func(place *model.Place) (*model.PlaceOccupancyLog, error)) (*model.Place, error) {
+	var place model.Place
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&place, id).Error; err != nil {
+			return err
+		}
+		entry, err := apply(&place)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&place).Updates(map[string]interface{}{
+			"capacity":          place.Capacity,
+			"occupancy":         place.Occupancy,
+			"status":            place.Status,
+			"status_updated_at": place.StatusUpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &place, nil
+}
+
+func (r *placeRepository) FindOccupancyHistory(placeID uint, limit int) ([]model.PlaceOccupancyLog, error) {
+	var history []model.PlaceOccupancyLog
+	err := r.db.Where("place_id = ?", placeID).
+		Order("recorded_at DESC").
+		Limit(limit).
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
 func (r *placeRepository) Delete(id uint) error {
 	return r.db.Delete(&model.Place{}, id).Error
 }
+
+// importedPlaceColumns are the columns owned by the bulk import (収容状況・external_id は含めない)
+var importedPlaceColumns = []string{
+	"name", "name_kana", "address", "lat", "lon", "url", "tel", "category",
+	"safe_for_flood", "safe_for_landslide", "safe_for_storm_surge", "safe_for_earthquake",
+	"safe_for_tsunami", "safe_for_large_fire", "safe_for_inland_water", "safe_for_volcano",
+}
+
+// SaveImported writes the result of a bulk import in a single transaction
+func (r *placeRepository) SaveImported(creates []*model.Place, updates []*model.Place) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(creates) > 0 {
+			if err := tx.CreateInBatches(creates, 500).Error; err != nil {
+				return err
+			}
+		}
+		// 取込データが持つ列だけを更新し、取込中に行われた収容状況の更新を上書きしない
+		for _, place := range updates {
+			if err := tx.Model(place).Select(importedPlaceColumns).Updates(place).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
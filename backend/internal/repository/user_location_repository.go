@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+)
+
+type userLocationRepository struct {
+	db *gorm.DB
+}
+
+// NewUserLocationRepository creates a new saved location repository
+func NewUserLocationRepository(db *gorm.DB) repository.UserLocationRepository {
+	return &userLocationRepository{db: db}
+}
+
+func (r *userLocationRepository) Create(location *model.UserLocation) error {
+	return r.db.Create(location).Error
+}
+
+func (r *userLocationRepository) FindByID(id uint) (*model.UserLocation, error) {
+	var location model.UserLocation
+	if err := r.db.First(&location, id).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *userLocationRepository) FindByUserID(userID uint) ([]model.UserLocation, error) {
+	var locations []model.UserLocation
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *userLocationRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.UserLocation{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *userLocationRepository) Update(location *model.UserLocation) error {
+	return r.db.Save(location).Error
+}
+
+func (r *userLocationRepository) Delete(id uint) error {
+	return r.db.Delete(&model.UserLocation{}, id).Error
+}
@@ -56,6 +56,19 @@ func (r *userRepository) Update(user *model.User) error {
 	return r.db.Save(user).Error
 }
 
+func (r *userRepository) UpdateColumns(id uint, columns map[string]interface{}) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(columns).Error
+}
+
 func (r *userRepository) Delete(id uint) error {
 	return r.db.Delete(&model.User{}, id).Error
 }
+
+func (r *userRepository) DeleteInTransaction(id uint, fn func() error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.User{}, id).Error; err != nil {
+			return err
+		}
+		return fn()
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"zerodelay/internal/config"
+	"zerodelay/internal/domain/model"
 	"zerodelay/internal/handler"
 	custommiddleware "zerodelay/internal/middleware"
 	"zerodelay/internal/service"
@@ -17,9 +19,14 @@ func SetupRoutes(
 	e *echo.Echo,
 	healthHandler *handler.HealthHandler,
 	userHandler *handler.UserHandler,
+	userLocationHandler *handler.UserLocationHandler,
 	placeHandler *handler.PlaceHandler,
+	placeImportHandler *handler.PlaceImportHandler,
+	hazardHandler *handler.HazardHandler,
 	authHandler *handler.AuthHandler,
+	emailChangeHandler *handler.EmailChangeHandler,
 	authService *service.AuthService,
+	userService *service.UserService,
 ) {
 	// Middleware
 	e.Use(middleware.Logger())
@@ -36,29 +43,74 @@ func SetupRoutes(
 	auth := v1.Group("/auth")
 	auth.POST("/signup", authHandler.SignUp)
 	auth.POST("/login", authHandler.Login)
+	auth.POST("/guest", authHandler.SignInAsGuest)
+	auth.POST("/refresh", authHandler.RefreshToken)
+	auth.POST("/password-reset", authHandler.RequestPasswordReset)
+	auth.POST("/password-reset/confirm", authHandler.ConfirmPasswordReset)
+	auth.POST("/resend-verification", authHandler.ResendVerification)
+	auth.POST("/verify-email", authHandler.VerifyEmail)
+	auth.GET("/action", authHandler.HandleEmailAction)
+	auth.POST("/email-change/confirm", emailChangeHandler.ConfirmChange)
+	auth.GET("/email-change/confirm", emailChangeHandler.ConfirmLink)
 
 	// Protected auth routes (require authentication)
 	auth.POST("/logout", authHandler.Logout, custommiddleware.FirebaseAuthMiddleware(authService))
+	auth.POST("/upgrade", authHandler.UpgradeGuest, custommiddleware.FirebaseAuthMiddleware(authService))
 
 	// Protected API routes (require authentication)
 	v1.Use(custommiddleware.FirebaseAuthMiddleware(authService))
 
+	// Authorization
+	requireAdmin := custommiddleware.RequireRole(userService, model.RoleMunicipalAdmin)
+	requireStaff := custommiddleware.RequireRole(userService, model.RoleShelterStaff, model.RoleMunicipalAdmin)
+	requireSelfOrAdmin := custommiddleware.RequireSelfOrRole(userService, "id", model.RoleMunicipalAdmin)
+	// 失効済みセッションを拒否（アカウント・個人情報・データ更新などの重要なルート）
+	requireNotRevoked := custommiddleware.RequireNotRevoked(authService)
+
 	// User routes
-	users := v1.Group("/users")
-	users.GET("", userHandler.GetAllUsers)
-	users.GET("/:id", userHandler.GetUser)
-	users.POST("", userHandler.CreateUser)
-	users.PUT("/:id", userHandler.UpdateUser)
-	users.DELETE("/:id", userHandler.DeleteUser)
+	users := v1.Group("/users", requireNotRevoked)
+	users.GET("", userHandler.GetAllUsers, requireAdmin)
+	users.GET("/:id", userHandler.GetUser, requireSelfOrAdmin)
+	users.POST("", userHandler.CreateUser, requireAdmin)
+	users.PUT("/:id", userHandler.UpdateUser, requireSelfOrAdmin)
+	users.DELETE("/:id", userHandler.DeleteUser, requireSelfOrAdmin)
+	users.PUT("/:id/role", userHandler.UpdateRole, requireAdmin)
+	users.GET("/me", userHandler.GetMe)           // プロフィール取得（自分自身）
 	users.PATCH("/me", userHandler.UpdateProfile) // プロフィール更新（自分自身）
+	users.DELETE("/me", userHandler.DeleteMe)     // アカウント削除（自分自身）
+
+	// Email change routes（自分自身、新しいアドレスでの確認後に反映）
+	users.POST("/me/email", emailChangeHandler.RequestChange)
+	users.GET("/me/email", emailChangeHandler.GetPending)
+	users.DELETE("/me/email", emailChangeHandler.CancelChange)
+
+	// Saved location routes（自分自身）
+	locations := users.Group("/me/locations")
+	locations.GET("", userLocationHandler.ListLocations)
+	locations.POST("", userLocationHandler.CreateLocation)
+	locations.GET("/:locationId", userLocationHandler.GetLocation)
+	locations.PUT("/:locationId", userLocationHandler.UpdateLocation)
+	locations.DELETE("/:locationId", userLocationHandler.DeleteLocation)
 
 	// Place routes
+	v1.GET("/places.geojson", placeHandler.GetPlacesGeoJSON)
 	places := v1.Group("/places")
 	places.GET("", placeHandler.GetAllPlaces)
+	places.GET("/nearby", placeHandler.GetNearbyPlaces)
 	places.GET("/:id", placeHandler.GetPlace)
-	places.POST("", placeHandler.CreatePlace)
-	places.PUT("/:id", placeHandler.UpdatePlace)
-	places.DELETE("/:id", placeHandler.DeletePlace)
+	places.POST("", placeHandler.CreatePlace, requireNotRevoked, requireAdmin)
+	places.PUT("/:id", placeHandler.UpdatePlace, requireNotRevoked, requireAdmin)
+	places.DELETE("/:id", placeHandler.DeletePlace, requireNotRevoked, requireAdmin)
+	places.PUT("/:id/occupancy", placeHandler.UpdateOccupancy, requireNotRevoked, requireStaff)
+	places.GET("/:id/occupancy/history", placeHandler.GetOccupancyHistory, requireStaff)
+
+	// Hazard routes
+	hazards := v1.Group("/hazards")
+	hazards.GET("/at", hazardHandler.GetHazardsAt)
+
+	// Admin routes
+	admin := v1.Group("/admin", requireNotRevoked, requireAdmin)
+	admin.POST("/places/import", placeImportHandler.ImportPlaces)
 }
 
 func buildCORSConfig() middleware.CORSConfig {
@@ -75,7 +127,7 @@ func buildCORSConfig() middleware.CORSConfig {
 	return middleware.CORSConfig{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{echo.GET, echo.POST, echo.PUT, echo.PATCH, echo.DELETE, echo.OPTIONS},
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, config.CSRFHeaderName},
 		ExposeHeaders:    []string{echo.HeaderAuthorization},
 		AllowCredentials: true,
 	}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+)
+
+// Actions throttled by AuthRateLimiter. 確認メール再送もパスワードを検証するため login と同じ枠で数える。
+// パスワードリセットのメール送信も同じ枠で数える。
+// ゲストの登録（upgrade）はアカウント作成にあたるため signup と同じ枠で数える
+const (
+	AuthActionLogin  = "login"
+	AuthActionSignUp = "signup"
+	AuthActionGuest  = "guest"
+)
+
+// throttlePolicy is the request limit and lockout rule of one kind of key
+type throttlePolicy struct {
+	limit  int
+	window time.Duration
+	// maxFailures is the number of consecutive failures that starts a lockout (0 = no lockout)
+	maxFailures int
+}
+
+var throttlePolicies = map[string]struct{ ip, email throttlePolicy }{
+	AuthActionLogin: {
+		ip:    throttlePolicy{limit: 30, window: 5 * time.Minute, maxFailures: 20},
+		email: throttlePolicy{limit: 10, window: 5 * time.Minute, maxFailures: 5},
+	},
+	AuthActionSignUp: {
+		ip:    throttlePolicy{limit: 5, window: time.Hour},
+		email: throttlePolicy{limit: 3, window: time.Hour},
+	},
+	// ゲストはメールアドレスを持たないため IP 単位のみ。
+	// 避難所や携帯回線（キャリアグレード NAT）では多くの利用者が同じ IP を共有するため、
+	// 大量作成を防げる範囲で大きめの上限にする
+	AuthActionGuest: {
+		ip: throttlePolicy{limit: 60, window: 5 * time.Minute},
+	},
+}
+
+const (
+	// lockoutBase doubles with every failure past maxFailures, up to lockoutMax
+	lockoutBase = time.Minute
+	lockoutMax  = time.Hour
+	// failureResetAfter forgets consecutive failures after this long without a new one
+	failureResetAfter = time.Hour
+	// throttleStaleAfter / throttlePruneInterval control removal of idle entries from the store
+	throttleStaleAfter    = 2 * time.Hour
+	throttlePruneInterval = 10 * time.Minute
+)
+
+// AuthRateLimiter limits attempts on the public auth endpoints per IP address and per email,
+// and locks out keys with progressively longer lockouts after repeated failed logins.
+type AuthRateLimiter struct {
+	store repository.AuthThrottleRepository
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewAuthRateLimiter creates a rate limiter backed by the given store
+func NewAuthRateLimiter(store repository.AuthThrottleRepository) *AuthRateLimiter {
+	return &AuthRateLimiter{store: store}
+}
+
+type throttleKey struct {
+	key    string
+	policy throttlePolicy
+}
+
+func (l *AuthRateLimiter) keys(action, ip, email string) []throttleKey {
+	policies := throttlePolicies[action]
+	keys := []throttleKey{{key: action + ":ip:" + ip, policy: policies.ip}}
+	if email = normalizeThrottleEmail(email); email != "" {
+		keys = append(keys, throttleKey{key: action + ":email:" + email, policy: policies.email})
+	}
+	return keys
+}
+
+// Allow counts an attempt and returns a too_many_attempts AuthError with RetryAfter when it is not allowed.
+// ストアの障害時は認証自体を止めないよう、ログを出して許可する
+func (l *AuthRateLimiter) Allow(ctx context.Context, action, ip, email string) error {
+	now := time.Now()
+	l.pruneIfDue(ctx, now)
+	keys := l.keys(action, ip, email)
+
+	// 1. ロックアウト中か（ロック中の試行はカウントしない）
+	for _, k := range keys {
+		if k.policy.maxFailures == 0 {
+			continue
+		}
+		until, err := l.store.LockedUntil(ctx, k.key, now)
+		if err != nil {
+			log.Printf("[ERROR] Failed to read lockout of %s: %v", k.key, err)
+			continue
+		}
+		if !until.IsZero() {
+			log.Printf("[WARN] Rejected %s attempt: %s is locked until %s", action, k.key, until.Format(time.RFC3339))
+			return tooManyAttempts(until.Sub(now))
+		}
+	}
+
+	// 2. 時間枠あたりの試行回数
+	for _, k := range keys {
+		count, windowStart, err := l.store.Hit(ctx, k.key, k.policy.window, now)
+		if err != nil {
+			log.Printf("[ERROR] Failed to count attempt of %s: %v", k.key, err)
+			continue
+		}
+		if count > k.policy.limit {
+			log.Printf("[WARN] Rejected %s attempt: %s exceeded %d per %s", action, k.key, k.policy.limit, k.policy.window)
+			return tooManyAttempts(windowStart.Add(k.policy.window).Sub(now))
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt and locks out keys that failed too often in a row
+func (l *AuthRateLimiter) RecordFailure(ctx context.Context, action, ip, email string) {
+	now := time.Now()
+	for _, k := range l.keys(action, ip, email) {
+		if k.policy.maxFailures == 0 {
+			continue
+		}
+		failures, err := l.store.RecordFailure(ctx, k.key, failureResetAfter, now)
+		if err != nil {
+			log.Printf("[ERROR] Failed to record failure of %s: %v", k.key, err)
+			continue
+		}
+		if failures < k.policy.maxFailures {
+			continue
+		}
+		lockout := lockoutDuration(failures - k.policy.maxFailures)
+		if err := l.store.Lock(ctx, k.key, now.Add(lockout)); err != nil {
+			log.Printf("[ERROR] Failed to lock %s: %v", k.key, err)
+			continue
+		}
+		log.Printf("[WARN] Locked %s for %s after %d consecutive failures", k.key, lockout, failures)
+	}
+}
+
+// RecordSuccess clears the failures of the email.
+// IPアドレスのカウントは残す（攻撃者が自分のアカウントでログインしてリセットできないように）
+func (l *AuthRateLimiter) RecordSuccess(ctx context.Context, action, email string) {
+	email = normalizeThrottleEmail(email)
+	if email == "" {
+		return
+	}
+	if err := l.store.ResetFailures(ctx, action+":email:"+email); err != nil {
+		log.Printf("[ERROR] Failed to reset failures of %s: %v", email, err)
+	}
+}
+
+// pruneIfDue removes idle entries at most once per throttlePruneInterval
+func (l *AuthRateLimiter) pruneIfDue(ctx context.Context, now time.Time) {
+	l.mu.Lock()
+	if now.Sub(l.lastPrune) < throttlePruneInterval {
+		l.mu.Unlock()
+		return
+	}
+	l.lastPrune = now
+	l.mu.Unlock()
+
+	if err := l.store.DeleteStale(ctx, now.Add(-throttleStaleAfter), now); err != nil {
+		log.Printf("[WARN] Failed to prune auth throttle entries: %v", err)
+	}
+}
+
+// lockoutDuration returns lockoutBase doubled for each extra failure, capped at lockoutMax
+func lockoutDuration(extraFailures int) time.Duration {
+	lockout := lockoutBase
+	for i := 0; i < extraFailures && lockout < lockoutMax; i++ {
+		lockout *= 2
+	}
+	if lockout > lockoutMax {
+		lockout = lockoutMax
+	}
+	return lockout
+}
+
+func tooManyAttempts(retryAfter time.Duration) error {
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	return &model.AuthError{Code: model.AuthErrTooManyAttempts, RetryAfter: retryAfter}
+}
+
+func normalizeThrottleEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -5,20 +5,33 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 
 	"zerodelay/internal/domain/model"
 	"zerodelay/internal/domain/repository"
 )
 
+var (
+	ErrRefreshTokenRequired = errors.New("refresh token is required")
+	ErrEmailRequired        = errors.New("email is required")
+	ErrEmailAlreadyVerified = model.ErrAuthEmailVerified
+	ErrActionCodeRequired   = errors.New("oobCode is required")
+)
+
 type AuthService struct {
-	authRepo repository.AuthRepository
-	userRepo repository.UserRepository
+	authRepo          repository.AuthRepository
+	userRepo          repository.UserRepository
+	verificationCache *emailVerificationCache
 }
 
 func NewAuthService(authRepo repository.AuthRepository, userRepo repository.UserRepository) *AuthService {
 	return &AuthService{
-		authRepo: authRepo,
-		userRepo: userRepo,
+		authRepo:          authRepo,
+		userRepo:          userRepo,
+		verificationCache: newEmailVerificationCache(emailVerificationCacheTTL),
 	}
 }
 
@@ -39,6 +52,7 @@ func (s *AuthService) SignUp(ctx context.Context, req *model.SignUpRequest) (*mo
 	user := &model.User{
 		FirebaseUID: authResp.LocalID,
 		Email:       authResp.Email,
+		Role:        model.RoleResident,
 		// Name, NameKana, Old, Sex, Setting は初期値（空/ゼロ値）
 	}
 
@@ -60,21 +74,105 @@ func (s *AuthService) SignUp(ctx context.Context, req *model.SignUpRequest) (*mo
 	return authResp, nil
 }
 
+// SignInAsGuest creates an anonymous auth user and its guest row so the app can be used without registering.
+// 設定や位置情報はゲストのまま保存でき、UpgradeGuest で登録しても UID が変わらないため引き継がれる
+func (s *AuthService) SignInAsGuest(ctx context.Context) (*model.AuthResponse, error) {
+	// 1. Firebase で匿名ユーザーを作成
+	authResp, err := s.authRepo.SignUpAnonymously(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. PostgreSQL にゲストの行を保存（メールアドレスは空）
+	user := &model.User{
+		FirebaseUID: authResp.LocalID,
+		Role:        model.RoleResident,
+		IsGuest:     true,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		if delErr := s.authRepo.DeleteUser(ctx, authResp.LocalID); delErr != nil {
+			log.Printf("[ERROR] Failed to rollback Firebase user %s: %v", authResp.LocalID, delErr)
+		}
+		return nil, fmt.Errorf("failed to create user in database: %w", err)
+	}
+
+	// 3. ゲストは確認するメールアドレスがないため、そのままトークンを返す
+	authResp.User = user
+	return authResp, nil
+}
+
+// UpgradeGuest links an email and password to the caller's guest account.
+// anonymous is the anonymous claim of the caller's token; both it and users.is_guest must be set.
+// UID はそのままなので保存済みのデータは失われない。登録後はメールアドレスの確認が完了するまで API を利用できない
+func (s *AuthService) UpgradeGuest(ctx context.Context, uid string, anonymous bool, idToken string, req *model.UpgradeGuestRequest) (*model.AuthResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		return nil, ErrEmailRequired
+	}
+
+	// 1. ゲストであることを確認（DB の is_guest だけでなく、トークン自体が匿名ユーザーのものであること）
+	if !anonymous {
+		return nil, model.ErrAuthNotGuest
+	}
+	user, err := s.userRepo.FindByFirebaseUID(uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !user.IsGuest {
+		return nil, model.ErrAuthNotGuest
+	}
+	if other, err := s.userRepo.FindByEmail(email); err == nil && other.ID != user.ID {
+		return nil, model.ErrAuthEmailExists
+	}
+
+	// 2. Firebase で匿名ユーザーにメールアドレスとパスワードを紐付け
+	authResp, err := s.authRepo.LinkEmailPassword(ctx, idToken, email, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	// 匿名トークンで確認済み扱いにしていたキャッシュを破棄する
+	s.verificationCache.delete(uid)
+
+	// 3. メール確認リンクを送信（失敗しても登録は続行。確認メールは再送できる）
+	if err := s.authRepo.SendEmailVerification(ctx, authResp.IDToken); err != nil {
+		log.Printf("[WARN] Verification email was not sent to upgraded guest %s: %v", uid, err)
+	}
+
+	// 4. ゲストの行を通常のユーザーに更新
+	// Firebase 側は登録済みのため、失敗しても整合ジョブ（user reconciliation）で同期される
+	user.Email = authResp.Email
+	user.IsGuest = false
+	if err := s.userRepo.Update(user); err != nil {
+		log.Printf("[ERROR] Failed to update upgraded guest %s in database: %v", uid, err)
+	}
+	log.Printf("[INFO] Guest upgraded to registered user: %s", uid)
+
+	// 5. サインアップと同じく、メール確認後にログインしてもらうためトークンは返さない
+	authResp.User = user
+	authResp.IDToken = ""
+	authResp.RefreshToken = ""
+	authResp.ExpiresIn = ""
+	return authResp, nil
+}
+
 func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*model.AuthResponse, error) {
 	// 1. Firebase で認証
 	authResp, err := s.authRepo.Login(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, signInError(err)
 	}
 
 	// 2. メールアドレスが確認済みかチェック
 	user, err := s.authRepo.GetUser(ctx, authResp.LocalID)
 	if err != nil {
-		return nil, errors.New("failed to get user information")
+		return nil, fmt.Errorf("failed to get user information: %w", err)
 	}
 
 	if !user.EmailVerified {
-		return nil, errors.New("email not verified. Please check your email and verify your account")
+		return nil, model.ErrAuthEmailNotVerified
 	}
 
 	// 3. PostgreSQL からユーザー情報を取得
@@ -90,14 +188,129 @@ func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 	return authResp, nil
 }
 
-func (s *AuthService) VerifyIDToken(ctx context.Context, idToken string) (string, error) {
+// RequestPasswordReset sends a password reset email.
+// 登録されていないメールアドレスでも成功として扱い、アカウントの有無を外部に漏らさない
+func (s *AuthService) RequestPasswordReset(ctx context.Context, req *model.PasswordResetRequest) error {
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return ErrEmailRequired
+	}
+
+	if err := s.authRepo.SendPasswordResetEmail(ctx, email); err != nil {
+		if errors.Is(err, model.ErrAuthEmailNotFound) {
+			log.Printf("[INFO] Password reset requested for unknown email: %s", email)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ApplyEmailVerification marks the email as verified with the code from a verification email
+func (s *AuthService) ApplyEmailVerification(ctx context.Context, req *model.ActionCodeRequest) error {
+	if strings.TrimSpace(req.OobCode) == "" {
+		return ErrActionCodeRequired
+	}
+	return s.authRepo.ApplyEmailVerification(ctx, req.OobCode)
+}
+
+// ConfirmPasswordReset sets a new password with the code from a password reset email
+func (s *AuthService) ConfirmPasswordReset(ctx context.Context, req *model.ConfirmPasswordResetRequest) error {
+	if strings.TrimSpace(req.OobCode) == "" {
+		return ErrActionCodeRequired
+	}
+	return s.authRepo.ConfirmPasswordReset(ctx, req.OobCode, req.NewPassword)
+}
+
+// ResendVerification re-sends the verification email to a user who has not verified yet.
+// 未確認ユーザーはログインできないため、メールアドレスとパスワードで本人確認してから送信する
+func (s *AuthService) ResendVerification(ctx context.Context, req *model.ResendVerificationRequest) error {
+	// 1. Firebase で認証してIDトークンを取得
+	authResp, err := s.authRepo.Login(ctx, &model.LoginRequest{Email: req.Email, Password: req.Password})
+	if err != nil {
+		return signInError(err)
+	}
+
+	// 2. 確認済みなら送信しない
+	user, err := s.authRepo.GetUser(ctx, authResp.LocalID)
+	if err != nil {
+		return fmt.Errorf("failed to get user information: %w", err)
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	// 3. 確認メールを送信
+	return s.authRepo.SendEmailVerification(ctx, authResp.IDToken)
+}
+
+// signInError hides whether the email or the password was wrong.
+// メールアドレス列挙保護が無効なプロジェクトでは Firebase が EMAIL_NOT_FOUND / INVALID_PASSWORD を返すため、同じエラーにまとめる
+func signInError(err error) error {
+	authErr, ok := model.AsAuthError(err)
+	if !ok {
+		return err
+	}
+	if authErr.Code == model.AuthErrEmailNotFound || authErr.Code == model.AuthErrUserNotFound {
+		return model.NewAuthError(model.AuthErrInvalidCredentials, authErr.ProviderCode)
+	}
+	return err
+}
+
+// RefreshToken exchanges a refresh token for a new ID token
+func (s *AuthService) RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.AuthResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, ErrRefreshTokenRequired
+	}
+	return s.authRepo.RefreshToken(ctx, req.RefreshToken)
+}
+
+func (s *AuthService) VerifyIDToken(ctx context.Context, idToken string) (*model.TokenInfo, error) {
 	return s.authRepo.VerifyIDToken(ctx, idToken)
 }
 
-func (s *AuthService) IsEmailVerified(ctx context.Context, uid string) (bool, error) {
-	user, err := s.authRepo.GetUser(ctx, uid)
+// CreateSessionCookie issues a Firebase session cookie for a freshly signed-in ID token
+func (s *AuthService) CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error) {
+	return s.authRepo.CreateSessionCookie(ctx, idToken, expiresIn)
+}
+
+func (s *AuthService) VerifySessionCookie(ctx context.Context, sessionCookie string) (*model.TokenInfo, error) {
+	return s.authRepo.VerifySessionCookie(ctx, sessionCookie)
+}
+
+// VerifyIDTokenAndCheckRevoked is VerifyIDToken plus a check that the user's sessions were not revoked
+func (s *AuthService) VerifyIDTokenAndCheckRevoked(ctx context.Context, idToken string) (*model.TokenInfo, error) {
+	return s.authRepo.VerifyIDTokenAndCheckRevoked(ctx, idToken)
+}
+
+// VerifySessionCookieAndCheckRevoked is VerifySessionCookie plus a revocation check
+func (s *AuthService) VerifySessionCookieAndCheckRevoked(ctx context.Context, sessionCookie string) (*model.TokenInfo, error) {
+	return s.authRepo.VerifySessionCookieAndCheckRevoked(ctx, sessionCookie)
+}
+
+// RevokeAllSessions signs the user out of every device.
+// Firebase の失効はユーザー単位のため、特定の端末だけを失効させることはできない
+func (s *AuthService) RevokeAllSessions(ctx context.Context, uid string) error {
+	return s.authRepo.RevokeRefreshTokens(ctx, uid)
+}
+
+// IsEmailVerified reports whether the token's user has verified their email.
+// トークンの email_verified クレームが true なら Firebase へ問い合わせない。
+// false の場合はトークン発行後に確認された可能性があるため、短時間キャッシュ付きで Firebase に問い合わせる。
+// ゲスト（匿名トークン）は登録するまで確認済みとして扱う。登録後に残っている匿名トークンは通さない。
+func (s *AuthService) IsEmailVerified(ctx context.Context, token *model.TokenInfo) (bool, error) {
+	if token.EmailVerified {
+		return true, nil
+	}
+	if verified, ok := s.verificationCache.get(token.UID); ok {
+		return verified, nil
+	}
+
+	user, err := s.authRepo.GetUser(ctx, token.UID)
 	if err != nil {
 		return false, err
 	}
-	return user.EmailVerified, nil
+	verified := user.EmailVerified || (token.Anonymous && user.Anonymous)
+	s.verificationCache.set(token.UID, verified)
+	return verified, nil
 }
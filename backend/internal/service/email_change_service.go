@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+	mailer "zerodelay/internal/mail"
+)
+
+var (
+	ErrEmailUnchanged           = errors.New("new email is the same as the current email")
+	ErrEmailChangeNotFound      = errors.New("no pending email change")
+	ErrEmailChangeTokenRequired = errors.New("token is required")
+	ErrMailerUnavailable        = errors.New("no mailer is configured for email changes")
+)
+
+const (
+	// emailChangeTTL is how long the confirmation link stays valid
+	emailChangeTTL = 24 * time.Hour
+	// emailChangeResendInterval blocks sending confirmation emails in quick succession
+	emailChangeResendInterval = time.Minute
+)
+
+// EmailChangeService changes a user's email only after the new address is confirmed.
+// 新しいアドレスに確認リンクを送り、リンクが開かれた時点で Firebase と users の両方を更新する
+type EmailChangeService struct {
+	changeRepo repository.EmailChangeRepository
+	userRepo   repository.UserRepository
+	authRepo   repository.AuthRepository
+	mailer     mailer.Mailer
+	confirmURL string
+}
+
+// NewEmailChangeService creates a new email change service.
+// mailer が nil の場合は確認メールを送れないため、RequestChange は ErrMailerUnavailable を返す
+func NewEmailChangeService(
+	changeRepo repository.EmailChangeRepository,
+	userRepo repository.UserRepository,
+	authRepo repository.AuthRepository,
+	mailer mailer.Mailer,
+	confirmURL string,
+) *EmailChangeService {
+	return &EmailChangeService{
+		changeRepo: changeRepo,
+		userRepo:   userRepo,
+		authRepo:   authRepo,
+		mailer:     mailer,
+		confirmURL: confirmURL,
+	}
+}
+
+// RequestChange stores the new email as pending and sends a confirmation link to it.
+// 現在のアドレスにも変更リクエストがあったことを通知する
+func (s *EmailChangeService) RequestChange(ctx context.Context, firebaseUID string, newEmail string) (*model.EmailChange, error) {
+	if s.mailer == nil {
+		return nil, ErrMailerUnavailable
+	}
+	user, err := s.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	// ゲストはパスワードがないため、メールアドレスの登録は POST /api/v1/auth/upgrade で行う
+	if user.IsGuest {
+		return nil, model.ErrAuthGuestAccount
+	}
+
+	// 1. 入力チェック（Firebase と同じく小文字で保存する）
+	email := strings.ToLower(strings.TrimSpace(newEmail))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, model.ErrAuthInvalidEmail
+	}
+	if email == strings.ToLower(user.Email) {
+		return nil, ErrEmailUnchanged
+	}
+	if err := s.ensureEmailAvailable(email, user.ID); err != nil {
+		return nil, err
+	}
+
+	// 2. 確認メールの連続送信を防ぐ
+	if pending, err := s.changeRepo.FindByUserID(user.ID); err == nil {
+		if wait := time.Until(pending.CreatedAt.Add(emailChangeResendInterval)); wait > 0 {
+			return nil, &model.AuthError{Code: model.AuthErrTooManyAttempts, RetryAfter: wait}
+		}
+	}
+
+	// 3. トークンはハッシュだけを保存する
+	token, err := newEmailChangeToken()
+	if err != nil {
+		return nil, err
+	}
+	change := &model.EmailChange{
+		UserID:    user.ID,
+		NewEmail:  email,
+		TokenHash: hashEmailChangeToken(token),
+		ExpiresAt: time.Now().Add(emailChangeTTL),
+	}
+	if err := s.changeRepo.Replace(change); err != nil {
+		return nil, fmt.Errorf("failed to save email change: %w", err)
+	}
+
+	// 4. 新しいアドレスに確認リンクを送信（失敗したらリクエストを取り消す）
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "【ZeroDelay】メールアドレス変更の確認",
+		Body: "ZeroDelay のメールアドレスをこのアドレスに変更するリクエストを受け付けました。\n" +
+			"以下のリンクを開くと変更が完了します（有効期限: 24時間）。\n\n" +
+			s.confirmLink(token) + "\n\n" +
+			"心当たりがない場合は、このメールを破棄してください。",
+	}); err != nil {
+		log.Printf("[ERROR] Failed to send email change confirmation to %s: %v", email, err)
+		if delErr := s.changeRepo.DeleteByUserID(user.ID); delErr != nil {
+			log.Printf("[ERROR] Failed to discard email change of user %d: %v", user.ID, delErr)
+		}
+		return nil, fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+
+	// 5. 現在のアドレスに通知（失敗しても変更手続きは続ける）
+	s.notify(ctx, user.Email, "【ZeroDelay】メールアドレス変更のリクエスト",
+		"ZeroDelay のアカウントのメールアドレスを "+email+" に変更するリクエストがありました。\n"+
+			"新しいアドレスで確認が完了するまで、メールアドレスは変更されません。\n\n"+
+			"心当たりがない場合は、パスワードを変更してください。")
+
+	log.Printf("[INFO] Email change requested for user %d", user.ID)
+	return change, nil
+}
+
+// PendingChange returns the caller's pending email change
+func (s *EmailChangeService) PendingChange(firebaseUID string) (*model.EmailChange, error) {
+	user, err := s.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	change, err := s.changeRepo.FindByUserID(user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEmailChangeNotFound
+		}
+		return nil, err
+	}
+	if time.Now().After(change.ExpiresAt) {
+		return nil, ErrEmailChangeNotFound
+	}
+	return change, nil
+}
+
+// CancelChange discards the caller's pending email change
+func (s *EmailChangeService) CancelChange(firebaseUID string) error {
+	user, err := s.findUser(firebaseUID)
+	if err != nil {
+		return err
+	}
+	return s.changeRepo.DeleteByUserID(user.ID)
+}
+
+// ConfirmChange applies the pending change identified by the token from the confirmation email.
+// Firebase の更新は users の更新と同じトランザクション内で行い、失敗したら users も元に戻す。
+// コミットだけが失敗した場合のずれは、ユーザー突き合わせジョブが Firebase 側に合わせて解消する
+func (s *EmailChangeService) ConfirmChange(ctx context.Context, token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrEmailChangeTokenRequired
+	}
+
+	change, err := s.changeRepo.FindByTokenHash(hashEmailChangeToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ErrAuthInvalidActionCode
+		}
+		return err
+	}
+	if time.Now().After(change.ExpiresAt) {
+		if err := s.changeRepo.DeleteByUserID(change.UserID); err != nil {
+			log.Printf("[WARN] Failed to delete expired email change of user %d: %v", change.UserID, err)
+		}
+		return model.ErrAuthInvalidActionCode
+	}
+
+	user, err := s.userRepo.FindByID(change.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if err := s.ensureEmailAvailable(change.NewEmail, user.ID); err != nil {
+		return err
+	}
+
+	oldEmail := user.Email
+	err = s.changeRepo.Apply(change, func() error {
+		return s.authRepo.UpdateEmail(ctx, user.FirebaseUID, change.NewEmail)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notify(ctx, oldEmail, "【ZeroDelay】メールアドレスが変更されました",
+		"ZeroDelay のアカウントのメールアドレスが "+change.NewEmail+" に変更されました。\n\n"+
+			"心当たりがない場合は、至急お問い合わせください。")
+
+	log.Printf("[INFO] Email changed for user %d", user.ID)
+	return nil
+}
+
+func (s *EmailChangeService) findUser(firebaseUID string) (*model.User, error) {
+	user, err := s.userRepo.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ensureEmailAvailable rejects an email already used by another user
+func (s *EmailChangeService) ensureEmailAvailable(email string, userID uint) error {
+	other, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if other.ID != userID {
+		return model.ErrAuthEmailExists
+	}
+	return nil
+}
+
+func (s *EmailChangeService) notify(ctx context.Context, to, subject, body string) {
+	if s.mailer == nil {
+		return
+	}
+	if err := s.mailer.Send(ctx, mailer.Message{To: to, Subject: subject, Body: body}); err != nil {
+		log.Printf("[WARN] Failed to send notice to %s: %v", to, err)
+	}
+}
+
+func (s *EmailChangeService) confirmLink(token string) string {
+	sep := "?"
+	if strings.Contains(s.confirmURL, "?") {
+		sep = "&"
+	}
+	return s.confirmURL + sep + "token=" + url.QueryEscape(token)
+}
+
+func newEmailChangeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"log"
+	"sort"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/geo"
+)
+
+// Thresholds used for evacuation recommendations (内閣府「避難情報に関するガイドライン」に準拠)
+const (
+	// 浸水深ランク3（3.0m以上）は2階床上まで浸水する
+	verticalEvacuationMaxDepthRank = 2
+	// 浸水継続時間ランク3（1日以上）は屋内にとどまると孤立する恐れがある
+	longInundationDurationRank = 3
+)
+
+// AssessPoint returns every hazard that applies at the coordinate together with recommended actions
+func (s *HazardService) AssessPoint(lat, lon float64) (*model.HazardAssessment, error) {
+	if !geo.ValidCoordinate(lat, lon) {
+		return nil, ErrInvalidCoordinates
+	}
+
+	// 1. 外接矩形で候補を取得し、ポリゴンの内外判定で絞り込む
+	candidates, err := s.hazardRepo.FindByBBoxContaining(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []model.HazardMatch{}
+	for _, zone := range candidates {
+		if zone.Geometry == nil {
+			continue
+		}
+		polygons, err := geo.ParsePolygons(zone.Geometry.Type, zone.Geometry.Coordinates)
+		if err != nil {
+			log.Printf("[WARN] Skipping hazard zone %d with invalid geometry: %v", zone.ID, err)
+			continue
+		}
+		if !polygons.Contains(lon, lat) {
+			continue
+		}
+		matches = append(matches, hazardMatchFromZone(zone))
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Kind != matches[j].Kind {
+			return matches[i].Kind < matches[j].Kind
+		}
+		return matches[i].Source < matches[j].Source
+	})
+
+	// 2. 集計して推奨行動を決定
+	summary := summarizeHazards(matches)
+	return &model.HazardAssessment{
+		Lat:                lat,
+		Lon:                lon,
+		Hazards:            matches,
+		Summary:            summary,
+		RecommendedActions: recommendActions(summary, len(matches) > 0),
+	}, nil
+}
+
+func hazardMatchFromZone(zone model.HazardZone) model.HazardMatch {
+	match := model.HazardMatch{
+		Kind:        zone.Kind,
+		Source:      zone.Source,
+		SourceRiver: zone.SourceRiver,
+		Scenario:    zone.Scenario,
+		ZoneType:    zone.ZoneType,
+	}
+	if zone.DepthRank > 0 {
+		match.DepthRank = zone.DepthRank
+		match.DepthLabel = model.DepthRankLabel(zone.DepthRank)
+	}
+	if zone.DurationRank > 0 {
+		match.DurationRank = zone.DurationRank
+		match.DurationLabel = model.DurationRankLabel(zone.DurationRank)
+	}
+	return match
+}
+
+// summarizeHazards takes the worst case across all matched zones and scenarios
+func summarizeHazards(matches []model.HazardMatch) model.HazardSummary {
+	var summary model.HazardSummary
+	for _, m := range matches {
+		switch m.Kind {
+		case model.HazardKindInundationDepth:
+			if m.DepthRank > summary.MaxDepthRank {
+				summary.MaxDepthRank = m.DepthRank
+			}
+		case model.HazardKindInundationDuration:
+			if m.DurationRank > summary.MaxDurationRank {
+				summary.MaxDurationRank = m.DurationRank
+			}
+		case model.HazardKindHouseCollapse:
+			summary.HouseCollapseZone = true
+		case model.HazardKindLandslide:
+			summary.LandslideWarningArea = true
+			if m.ZoneType == model.ZoneTypeSpecialWarning {
+				summary.LandslideSpecialWarningArea = true
+			}
+		}
+	}
+	summary.MaxDepthLabel = model.DepthRankLabel(summary.MaxDepthRank)
+	summary.MaxDurationLabel = model.DurationRankLabel(summary.MaxDurationRank)
+
+	summary.VerticalEvacuationSufficient = summary.MaxDepthRank <= verticalEvacuationMaxDepthRank &&
+		summary.MaxDurationRank < longInundationDurationRank &&
+		!summary.HouseCollapseZone &&
+		!summary.LandslideWarningArea
+	return summary
+}
+
+// recommendActions builds human-readable recommendations, most urgent first
+func recommendActions(summary model.HazardSummary, hasHazards bool) []model.RecommendedAction {
+	actions := []model.RecommendedAction{}
+
+	if summary.HouseCollapseZone {
+		actions = append(actions, model.RecommendedAction{
+			Code:    "evacuate_house_collapse_zone",
+			Message: "家屋倒壊等氾濫想定区域内です。建物ごと流失・倒壊する恐れがあり、垂直避難では不十分です。区域外の避難場所へ早めに立退き避難してください。",
+		})
+	}
+	if summary.LandslideSpecialWarningArea {
+		actions = append(actions, model.RecommendedAction{
+			Code:    "evacuate_landslide_special_warning_area",
+			Message: "土砂災害特別警戒区域内です。建物が損壊し命に危険が及ぶ恐れがあります。大雨の際は早めに区域外の安全な場所へ立退き避難してください。",
+		})
+	} else if summary.LandslideWarningArea {
+		actions = append(actions, model.RecommendedAction{
+			Code:    "evacuate_landslide_warning_area",
+			Message: "土砂災害警戒区域内です。大雨の際は早めに区域外の安全な場所へ立退き避難してください。",
+		})
+	}
+
+	switch {
+	case summary.MaxDepthRank > verticalEvacuationMaxDepthRank:
+		actions = append(actions, model.RecommendedAction{
+			Code:    "vertical_evacuation_insufficient",
+			Message: "想定浸水深が" + summary.MaxDepthLabel + "で、2階以上まで浸水する恐れがあります。垂直避難では不十分なため、浸水想定区域外の避難場所へ立退き避難してください。",
+		})
+	case summary.MaxDepthRank == verticalEvacuationMaxDepthRank:
+		actions = append(actions, model.RecommendedAction{
+			Code:    "evacuate_or_move_upstairs",
+			Message: "想定浸水深が" + summary.MaxDepthLabel + "で、1階が浸水する恐れがあります。立退き避難が基本です。逃げ遅れた場合は2階以上へ垂直避難してください。",
+		})
+	case summary.MaxDepthRank > 0:
+		actions = append(actions, model.RecommendedAction{
+			Code:    "shallow_inundation",
+			Message: "想定浸水深は" + summary.MaxDepthLabel + "です。浸水が始まってからの移動は危険なため、屋内の高い場所で安全を確保してください。",
+		})
+	}
+
+	if summary.MaxDurationRank >= longInundationDurationRank {
+		actions = append(actions, model.RecommendedAction{
+			Code:    "long_inundation",
+			Message: "浸水が" + summary.MaxDurationLabel + "続く恐れがあり、屋内にとどまると長期間孤立する可能性があります。立退き避難してください。",
+		})
+	}
+
+	if !hasHazards {
+		actions = append(actions, model.RecommendedAction{
+			Code:    "no_mapped_hazard",
+			Message: "登録されているハザードマップの想定区域外です。想定を超える災害もあり得るため、気象情報と自治体の避難情報を確認してください。",
+		})
+	}
+
+	return actions
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+	"zerodelay/internal/geo"
+)
+
+var ErrInvalidHazardImport = errors.New("invalid hazard import")
+
+// Property names tried when options do not fix a value for every feature
+var (
+	depthRankProperties    = []string{"depth_rank", "rank", "浸水深ランク", "A31_001"}
+	durationRankProperties = []string{"duration_rank", "rank", "浸水継続時間ランク"}
+	zoneTypeProperties     = []string{"zone_type", "区域区分", "区分"}
+	sourceRiverProperties  = []string{"source_river", "river", "河川名"}
+	scenarioProperties     = []string{"scenario", "想定", "降雨規模"}
+)
+
+// HazardService handles business logic for hazard map layers
+type HazardService struct {
+	hazardRepo repository.HazardRepository
+}
+
+// NewHazardService creates a new hazard service
+func NewHazardService(hazardRepo repository.HazardRepository) *HazardService {
+	return &HazardService{hazardRepo: hazardRepo}
+}
+
+// ImportGeoJSON imports a GeoJSON FeatureCollection of hazard polygons.
+// 同じ Source の既存ゾーンは置き換えられるため、再取込しても重複しない
+func (s *HazardService) ImportGeoJSON(r io.Reader, opts model.HazardImportOptions) (*model.HazardImportReport, error) {
+	if strings.TrimSpace(opts.Source) == "" {
+		return nil, fmt.Errorf("%w: source is required", ErrInvalidHazardImport)
+	}
+	if opts.Kind == "" {
+		opts.Kind = model.HazardKindInundationDepth
+	}
+	if !opts.Kind.IsValid() {
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidHazardImport, opts.Kind)
+	}
+	if opts.Scenario != "" && !opts.Scenario.IsValid() {
+		return nil, fmt.Errorf("%w: unknown scenario %q", ErrInvalidHazardImport, opts.Scenario)
+	}
+
+	// 1. GeoJSONを読み込み
+	var fc model.FeatureCollection
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHazardImport, err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("%w: expected a FeatureCollection", ErrInvalidHazardImport)
+	}
+
+	report := &model.HazardImportReport{
+		Source:  opts.Source,
+		DryRun:  opts.DryRun,
+		Total:   len(fc.Features),
+		Skipped: []model.HazardImportSkip{},
+	}
+
+	// 2. 各フィーチャーをゾーンに変換
+	zones := make([]*model.HazardZone, 0, len(fc.Features))
+	for i, feature := range fc.Features {
+		zone, err := hazardZoneFromFeature(feature, opts)
+		if err != nil {
+			report.Skipped = append(report.Skipped, model.HazardImportSkip{Index: i, Reason: err.Error()})
+			continue
+		}
+		zones = append(zones, zone)
+	}
+	report.Imported = len(zones)
+
+	if opts.DryRun {
+		count, err := s.hazardRepo.CountBySource(opts.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count existing hazard zones: %w", err)
+		}
+		report.Replaced = count
+		log.Printf("[INFO] Hazard import dry run for %s: %d zones, %d skipped, %d to replace", opts.Source, report.Imported, len(report.Skipped), count)
+		// dry run ではスキップの内訳を確認できるよう、拒否せず警告だけ出す
+		if err := checkHazardImportSkips(report, opts); err != nil {
+			log.Printf("[WARN] Hazard import for %s would be refused: %v", opts.Source, err)
+		}
+		return report, nil
+	}
+
+	// 3. ほとんど取り込めなかったファイルで既存のゾーンを消さない
+	if err := checkHazardImportSkips(report, opts); err != nil {
+		return nil, err
+	}
+
+	// 4. 同じ取込元のゾーンを置き換え
+	replaced, err := s.hazardRepo.ReplaceBySource(opts.Source, zones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save hazard zones: %w", err)
+	}
+	report.Replaced = replaced
+
+	log.Printf("[INFO] Hazard import completed for %s: %d zones, %d skipped, %d replaced", opts.Source, report.Imported, len(report.Skipped), replaced)
+	return report, nil
+}
+
+// checkHazardImportSkips refuses an import that would replace the source's zones with nothing,
+// or with a small part of the file, unless explicitly allowed
+func checkHazardImportSkips(report *model.HazardImportReport, opts model.HazardImportOptions) error {
+	skipped := len(report.Skipped)
+	if skipped == 0 {
+		return nil
+	}
+	example := fmt.Sprintf("feature %d: %s", report.Skipped[0].Index, report.Skipped[0].Reason)
+	if report.Imported == 0 && !opts.AllowEmpty {
+		return fmt.Errorf("%w: no features could be imported (%d skipped, e.g. %s); check the kind and property names, or allow an empty import to clear the source",
+			ErrInvalidHazardImport, skipped, example)
+	}
+	if report.Imported > 0 && float64(skipped) > float64(report.Total)*model.MaxHazardSkipRatio && !opts.AllowSkips {
+		return fmt.Errorf("%w: %d of %d features were skipped (e.g. %s); allow skips to import the rest",
+			ErrInvalidHazardImport, skipped, report.Total, example)
+	}
+	return nil
+}
+
+// hazardZoneFromFeature converts one GeoJSON feature to a HazardZone
+func hazardZoneFromFeature(feature model.Feature, opts model.HazardImportOptions) (*model.HazardZone, error) {
+	if feature.Geometry == nil {
+		return nil, errors.New("missing geometry")
+	}
+	polygons, err := geo.ParsePolygons(feature.Geometry.Type, feature.Geometry.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+	minLon, minLat, maxLon, maxLat := polygons.Bounds()
+	if !geo.ValidCoordinate(minLat, minLon) || !geo.ValidCoordinate(maxLat, maxLon) {
+		return nil, errors.New("coordinates out of range")
+	}
+
+	zone := &model.HazardZone{
+		Kind:        opts.Kind,
+		Source:      opts.Source,
+		SourceRiver: opts.SourceRiver,
+		Scenario:    opts.Scenario,
+		Geometry:    feature.Geometry,
+		MinLon:      minLon,
+		MinLat:      minLat,
+		MaxLon:      maxLon,
+		MaxLat:      maxLat,
+	}
+
+	if zone.SourceRiver == "" {
+		zone.SourceRiver = stringProperty(feature.Properties, sourceRiverProperties)
+	}
+	if zone.Scenario == "" {
+		zone.Scenario = parseFloodScenario(stringProperty(feature.Properties, scenarioProperties))
+	}
+
+	switch opts.Kind {
+	case model.HazardKindInundationDepth:
+		rank, ok := intProperty(feature.Properties, depthRankProperties)
+		if !ok || rank < 1 || rank > model.MaxDepthRank {
+			return nil, errors.New("missing or invalid depth rank")
+		}
+		zone.DepthRank = rank
+	case model.HazardKindInundationDuration:
+		rank, ok := intProperty(feature.Properties, durationRankProperties)
+		if !ok || rank < 1 || rank > model.MaxDurationRank {
+			return nil, errors.New("missing or invalid duration rank")
+		}
+		zone.DurationRank = rank
+	case model.HazardKindHouseCollapse, model.HazardKindLandslide:
+		zone.ZoneType = parseZoneType(opts.Kind, stringProperty(feature.Properties, zoneTypeProperties))
+	}
+
+	return zone, nil
+}
+
+// parseZoneType normalizes the sub-area notation of house-collapse and landslide layers
+func parseZoneType(kind model.HazardKind, v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if kind == model.HazardKindHouseCollapse {
+		switch {
+		case v == model.ZoneTypeBankErosion || strings.Contains(v, "河岸侵食"):
+			return model.ZoneTypeBankErosion
+		default:
+			return model.ZoneTypeFloodFlow
+		}
+	}
+
+	switch {
+	case v == model.ZoneTypeSpecialWarning || v == "red" || strings.Contains(v, "特別警戒"):
+		return model.ZoneTypeSpecialWarning
+	default:
+		return model.ZoneTypeWarning
+	}
+}
+
+// parseFloodScenario accepts the notations used by municipal hazard map datasets
+func parseFloodScenario(v string) model.FloodScenario {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "planned", "l1", "計画規模":
+		return model.FloodScenarioPlanned
+	case "maximum", "l2", "想定最大規模":
+		return model.FloodScenarioMaximum
+	}
+	return ""
+}
+
+func stringProperty(props map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if v, ok := props[key]; ok && v != nil {
+			if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func intProperty(props map[string]interface{}, keys []string) (int, bool) {
+	for _, key := range keys {
+		switch v := props[key].(type) {
+		case float64:
+			return int(v), true
+		case string:
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
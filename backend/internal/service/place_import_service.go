@@ -0,0 +1,343 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+	"zerodelay/internal/geo"
+)
+
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+// placeCSVColumns maps model fields to the accepted header names.
+// 自治体標準オープンデータセット（指定緊急避難場所一覧）と国土地理院の公開形式の両方に対応する
+var placeCSVColumns = map[string][]string{
+	"common_id":  {"共通ID"},
+	"city_code":  {"全国地方公共団体コード"},
+	"id":         {"ID", "NO", "No"},
+	"name":       {"名称", "施設・場所名", "施設名"},
+	"name_kana":  {"名称_カナ", "名称カナ", "施設・場所名カナ"},
+	"address":    {"所在地_連結表記", "住所", "所在地"},
+	"lat":        {"緯度"},
+	"lon":        {"経度"},
+	"tel":        {"電話番号"},
+	"url":        {"URL"},
+	"flood":      {"洪水"},
+	"landslide":  {"崖崩れ、土石流及び地滑り", "崖崩れ、土石流及び地すべり"},
+	"storm":      {"高潮"},
+	"earthquake": {"地震"},
+	"tsunami":    {"津波"},
+	"fire":       {"大規模な火事"},
+	"inland":     {"内水氾濫"},
+	"volcano":    {"火山現象"},
+}
+
+// placeCSVDisasterColumns maps disaster-type columns to model.DisasterType
+var placeCSVDisasterColumns = map[string]model.DisasterType{
+	"flood":      model.DisasterFlood,
+	"landslide":  model.DisasterLandslide,
+	"storm":      model.DisasterStormSurge,
+	"earthquake": model.DisasterEarthquake,
+	"tsunami":    model.DisasterTsunami,
+	"fire":       model.DisasterLargeFire,
+	"inland":     model.DisasterInlandWater,
+	"volcano":    model.DisasterVolcano,
+}
+
+// PlaceImportService imports municipal evacuation-site open data into places
+type PlaceImportService struct {
+	placeRepo repository.PlaceRepository
+}
+
+// NewPlaceImportService creates a new place import service
+func NewPlaceImportService(placeRepo repository.PlaceRepository) *PlaceImportService {
+	return &PlaceImportService{placeRepo: placeRepo}
+}
+
+// ImportCSV upserts places from an evacuation-site CSV by their external ID.
+// With opts.DryRun the report is computed but nothing is written.
+func (s *PlaceImportService) ImportCSV(r io.Reader, opts model.PlaceImportOptions) (*model.PlaceImportReport, error) {
+	if opts.Category == "" {
+		opts.Category = model.PlaceCategoryEmergencyEvacuationSite
+	}
+	if !opts.Category.IsValid() {
+		return nil, ErrInvalidCategory
+	}
+
+	// 1. CSVを読み込み（UTF-8 / Shift_JIS 両対応）
+	records, err := readImportCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 1 {
+		return nil, fmt.Errorf("%w: header row is missing", ErrInvalidImportFile)
+	}
+	columns := resolvePlaceCSVColumns(records[0])
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: name column (名称) not found", ErrInvalidImportFile)
+	}
+	if _, ok := columns["lat"]; !ok {
+		return nil, fmt.Errorf("%w: latitude column (緯度) not found", ErrInvalidImportFile)
+	}
+	if _, ok := columns["lon"]; !ok {
+		return nil, fmt.Errorf("%w: longitude column (経度) not found", ErrInvalidImportFile)
+	}
+
+	report := &model.PlaceImportReport{DryRun: opts.DryRun}
+
+	// 2. 各行をPlaceに変換（不正な行はスキップとして記録）
+	type parsedRow struct {
+		line  int
+		place *model.Place
+	}
+	var parsed []parsedRow
+	seen := make(map[string]int)
+	for i, record := range records[1:] {
+		line := i + 2 // ヘッダー行を1行目とする
+		if isBlankRecord(record) {
+			continue
+		}
+		report.Total++
+
+		place, reason := placeFromCSVRecord(record, columns, opts.Category)
+		if reason != "" {
+			addImportRow(report, line, place, model.PlaceImportSkip, reason)
+			continue
+		}
+		if prev, dup := seen[*place.ExternalID]; dup {
+			addImportRow(report, line, place, model.PlaceImportSkip, fmt.Sprintf("duplicate ID (first seen on line %d)", prev))
+			continue
+		}
+		seen[*place.ExternalID] = line
+		parsed = append(parsed, parsedRow{line: line, place: place})
+	}
+
+	// 3. 既存データと突き合わせて登録/更新/変更なしを判定
+	ids := make([]string, 0, len(parsed))
+	for _, row := range parsed {
+		ids = append(ids, *row.place.ExternalID)
+	}
+	existing, err := s.placeRepo.FindByExternalIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up existing places: %w", err)
+	}
+	byExternalID := make(map[string]*model.Place, len(existing))
+	for i := range existing {
+		byExternalID[*existing[i].ExternalID] = &existing[i]
+	}
+
+	var creates, updates []*model.Place
+	for _, row := range parsed {
+		current, ok := byExternalID[*row.place.ExternalID]
+		if !ok {
+			row.place.Status = model.PlaceStatusClosed
+			creates = append(creates, row.place)
+			addImportRow(report, row.line, row.place, model.PlaceImportInsert, "")
+			continue
+		}
+		if sameImportedAttributes(current, row.place) {
+			addImportRow(report, row.line, row.place, model.PlaceImportSkip, "unchanged")
+			continue
+		}
+		applyImportedAttributes(current, row.place)
+		updates = append(updates, current)
+		addImportRow(report, row.line, row.place, model.PlaceImportUpdate, "")
+	}
+
+	sort.SliceStable(report.Rows, func(i, j int) bool { return report.Rows[i].Line < report.Rows[j].Line })
+
+	if opts.DryRun {
+		log.Printf("[INFO] Place import dry run: %d inserts, %d updates, %d skips", report.Inserted, report.Updated, report.Skipped)
+		return report, nil
+	}
+
+	// 4. 1トランザクションで書き込み
+	if err := s.placeRepo.SaveImported(creates, updates); err != nil {
+		return nil, fmt.Errorf("failed to save imported places: %w", err)
+	}
+
+	log.Printf("[INFO] Place import completed: %d inserts, %d updates, %d skips", report.Inserted, report.Updated, report.Skipped)
+	return report, nil
+}
+
+// addImportRow records a row result and updates the counters
+func addImportRow(report *model.PlaceImportReport, line int, place *model.Place, action model.PlaceImportAction, reason string) {
+	row := model.PlaceImportRow{Line: line, Action: action, Reason: reason}
+	if place != nil {
+		row.Name = place.Name
+		if place.ExternalID != nil {
+			row.ExternalID = *place.ExternalID
+		}
+	}
+	report.Rows = append(report.Rows, row)
+
+	switch action {
+	case model.PlaceImportInsert:
+		report.Inserted++
+	case model.PlaceImportUpdate:
+		report.Updated++
+	case model.PlaceImportSkip:
+		report.Skipped++
+	}
+}
+
+// readImportCSV reads all records, decoding Shift_JIS when the input is not valid UTF-8
+func readImportCSV(r io.Reader) ([][]string, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(raw) {
+		decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unsupported character encoding", ErrInvalidImportFile)
+		}
+		raw = decoded
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	return records, nil
+}
+
+// resolvePlaceCSVColumns returns the column index of each known field
+func resolvePlaceCSVColumns(header []string) map[string]int {
+	normalized := make(map[string]int, len(header))
+	for i, h := range header {
+		normalized[normalizeCSVHeader(h)] = i
+	}
+
+	columns := make(map[string]int)
+	for field, names := range placeCSVColumns {
+		for _, name := range names {
+			if idx, ok := normalized[normalizeCSVHeader(name)]; ok {
+				columns[field] = idx
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// normalizeCSVHeader absorbs notation differences between municipalities
+func normalizeCSVHeader(h string) string {
+	h = strings.TrimSpace(h)
+	h = strings.TrimPrefix(h, "災害種別_")
+	h = strings.NewReplacer(" ", "", "　", "", ",", "、", "，", "、").Replace(h)
+	return strings.ToUpper(h)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// placeFromCSVRecord converts a CSV row to a Place. A non-empty reason means the row must be skipped.
+func placeFromCSVRecord(record []string, columns map[string]int, category model.PlaceCategory) (*model.Place, string) {
+	get := func(field string) string {
+		idx, ok := columns[field]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	place := &model.Place{
+		Name:     get("name"),
+		NameKana: get("name_kana"),
+		Address:  get("address"),
+		Tel:      get("tel"),
+		URL:      get("url"),
+		Category: category,
+	}
+
+	// 共通IDがあれば優先し、なければ 全国地方公共団体コード-ID を安定IDとする
+	externalID := get("common_id")
+	if externalID == "" && get("id") != "" {
+		externalID = get("id")
+		if code := get("city_code"); code != "" {
+			externalID = code + "-" + externalID
+		}
+	}
+	if externalID != "" {
+		place.ExternalID = &externalID
+	}
+
+	if externalID == "" {
+		return place, "missing ID"
+	}
+	if place.Name == "" {
+		return place, "missing name"
+	}
+
+	lat, latErr := strconv.ParseFloat(get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(get("lon"), 64)
+	if latErr != nil || lonErr != nil || !geo.ValidCoordinate(lat, lon) {
+		return place, "invalid coordinates"
+	}
+	place.Lat = lat
+	place.Lon = lon
+
+	for field, disaster := range placeCSVDisasterColumns {
+		place.SafeFor.Set(disaster, isTruthyCSVValue(get(field)))
+	}
+
+	return place, ""
+}
+
+// isTruthyCSVValue reports whether a disaster-type cell marks the place as certified
+func isTruthyCSVValue(v string) bool {
+	switch strings.ToUpper(strings.TrimSpace(v)) {
+	case "1", "○", "〇", "◯", "●", "TRUE", "有", "可":
+		return true
+	}
+	return false
+}
+
+// sameImportedAttributes reports whether the imported attributes are already up to date
+func sameImportedAttributes(current, imported *model.Place) bool {
+	return current.Name == imported.Name &&
+		current.NameKana == imported.NameKana &&
+		current.Address == imported.Address &&
+		current.Lat == imported.Lat &&
+		current.Lon == imported.Lon &&
+		current.Tel == imported.Tel &&
+		current.URL == imported.URL &&
+		current.Category == imported.Category &&
+		current.SafeFor == imported.SafeFor
+}
+
+// applyImportedAttributes copies the open-data attributes, keeping ID and live occupancy untouched
+func applyImportedAttributes(current, imported *model.Place) {
+	current.Name = imported.Name
+	current.NameKana = imported.NameKana
+	current.Address = imported.Address
+	current.Lat = imported.Lat
+	current.Lon = imported.Lon
+	current.Tel = imported.Tel
+	current.URL = imported.URL
+	current.Category = imported.Category
+	current.SafeFor = imported.SafeFor
+}
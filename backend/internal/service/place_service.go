@@ -2,13 +2,32 @@ package service
 
 import (
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 	"zerodelay/internal/domain/model"
 	"zerodelay/internal/domain/repository"
+	"zerodelay/internal/geo"
 )
 
-var ErrPlaceNotFound = errors.New("place not found")
+var (
+	ErrPlaceNotFound      = errors.New("place not found")
+	ErrInvalidCoordinates = errors.New("invalid coordinates")
+	ErrInvalidBoundingBox = errors.New("invalid bounding box")
+	ErrInvalidCategory    = errors.New("invalid place category")
+	ErrInvalidDisaster    = errors.New("invalid disaster type")
+	ErrInvalidStatus      = errors.New("invalid place status")
+	ErrInvalidOccupancy   = errors.New("invalid capacity or occupancy")
+)
+
+const (
+	DefaultNearbyRadiusMeters = 2000
+	MaxNearbyRadiusMeters     = 50000
+	DefaultNearbyLimit        = 20
+	MaxNearbyLimit            = 100
+	DefaultHistoryLimit       = 100
+	MaxHistoryLimit           = 1000
+)
 
 // PlaceService handles business logic for places
 type PlaceService struct {
@@ -21,6 +40,21 @@ func NewPlaceService(placeRepo repository.PlaceRepository) *PlaceService {
 }
 
 func (s *PlaceService) CreatePlace(place *model.Place) error {
+	if !geo.ValidCoordinate(place.Lat, place.Lon) {
+		return ErrInvalidCoordinates
+	}
+	if err := normalizeCategory(place); err != nil {
+		return err
+	}
+	if place.Status == "" {
+		place.Status = model.PlaceStatusClosed
+	}
+	if !place.Status.IsValid() {
+		return ErrInvalidStatus
+	}
+	if place.Capacity < 0 || place.Occupancy < 0 {
+		return ErrInvalidOccupancy
+	}
 	return s.placeRepo.Create(place)
 }
 
@@ -39,18 +73,136 @@ func (s *PlaceService) GetAllPlaces() ([]model.Place, error) {
 	return s.placeRepo.FindAll()
 }
 
+// SearchPlaces returns places matching the filter (all places when the filter is empty)
+func (s *PlaceService) SearchPlaces(filter model.PlaceFilter) ([]model.Place, error) {
+	if err := validatePlaceFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.placeRepo.FindByFilter(filter)
+}
+
+// NearbyPlaces returns places within the radius sorted by distance from the given point
+func (s *PlaceService) NearbyPlaces(query model.NearbyQuery) ([]model.PlaceWithDistance, error) {
+	if !geo.ValidCoordinate(query.Lat, query.Lon) {
+		return nil, ErrInvalidCoordinates
+	}
+	if err := validatePlaceFilter(query.Filter); err != nil {
+		return nil, err
+	}
+
+	// 未指定・範囲外の値はデフォルト/上限に丸める
+	if query.RadiusMeters <= 0 {
+		query.RadiusMeters = DefaultNearbyRadiusMeters
+	}
+	if query.RadiusMeters > MaxNearbyRadiusMeters {
+		query.RadiusMeters = MaxNearbyRadiusMeters
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultNearbyLimit
+	}
+	if query.Limit > MaxNearbyLimit {
+		query.Limit = MaxNearbyLimit
+	}
+
+	return s.placeRepo.FindNearby(query)
+}
+
 func (s *PlaceService) UpdatePlace(place *model.Place) error {
+	if !geo.ValidCoordinate(place.Lat, place.Lon) {
+		return ErrInvalidCoordinates
+	}
+	if err := normalizeCategory(place); err != nil {
+		return err
+	}
+
 	// Check if place exists
-	_, err := s.placeRepo.FindByID(place.ID)
+	existing, err := s.placeRepo.FindByID(place.ID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrPlaceNotFound
 		}
 		return err
 	}
+
+	// 収容状況は UpdateOccupancy 経由でのみ変更する（履歴を残すため）
+	place.Capacity = existing.Capacity
+	place.Occupancy = existing.Occupancy
+	place.Status = existing.Status
+	place.StatusUpdatedAt = existing.StatusUpdatedAt
+
+	// external_id は一括取込の更新キーのため、省略時は既存の値を残す（空文字を送った場合のみ解除する）
+	if place.ExternalID == nil {
+		place.ExternalID = existing.ExternalID
+	} else if *place.ExternalID == "" {
+		place.ExternalID = nil
+	}
+
 	return s.placeRepo.Update(place)
 }
 
+// UpdateOccupancy updates a shelter's capacity, occupancy and status and records the change in history.
+// 行をロックしてから現在の値に反映するため、同じ避難所への同時更新が失われない
+func (s *PlaceService) UpdateOccupancy(id uint, req *model.UpdateOccupancyRequest, updatedBy string) (*model.Place, error) {
+	if req.Status != nil && !req.Status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+
+	place, err := s.placeRepo.UpdateOccupancy(id, func(place *model.Place) (*model.PlaceOccupancyLog, error) {
+		// 1. 送信されたフィールドのみ反映
+		if req.Status != nil {
+			place.Status = *req.Status
+		}
+		if req.Capacity != nil {
+			place.Capacity = *req.Capacity
+		}
+		if req.Occupancy != nil {
+			place.Occupancy = *req.Occupancy
+		}
+		if place.Capacity < 0 || place.Occupancy < 0 {
+			return nil, ErrInvalidOccupancy
+		}
+
+		// 2. 開設中に定員へ達した場合は満員として扱う
+		if place.Status == model.PlaceStatusOpen && place.Capacity > 0 && place.Occupancy >= place.Capacity {
+			place.Status = model.PlaceStatusFull
+		}
+
+		// 3. 収容状況の更新と履歴の記録
+		now := time.Now()
+		place.StatusUpdatedAt = &now
+		return &model.PlaceOccupancyLog{
+			PlaceID:    place.ID,
+			Status:     place.Status,
+			Capacity:   place.Capacity,
+			Occupancy:  place.Occupancy,
+			UpdatedBy:  updatedBy,
+			RecordedAt: now,
+		}, nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlaceNotFound
+		}
+		return nil, err
+	}
+	return place, nil
+}
+
+// GetOccupancyHistory returns the most recent occupancy history entries of a place
+func (s *PlaceService) GetOccupancyHistory(id uint, limit int) ([]model.PlaceOccupancyLog, error) {
+	if _, err := s.GetPlace(id); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	if limit > MaxHistoryLimit {
+		limit = MaxHistoryLimit
+	}
+	return s.placeRepo.FindOccupancyHistory(id, limit)
+}
+
 func (s *PlaceService) DeletePlace(id uint) error {
 	// Check if place exists
 	_, err := s.placeRepo.FindByID(id)
@@ -62,3 +214,40 @@ func (s *PlaceService) DeletePlace(id uint) error {
 	}
 	return s.placeRepo.Delete(id)
 }
+
+// normalizeCategory defaults an empty category to "other" and rejects unknown values
+func normalizeCategory(place *model.Place) error {
+	if place.Category == "" {
+		place.Category = model.PlaceCategoryOther
+	}
+	if !place.Category.IsValid() {
+		return ErrInvalidCategory
+	}
+	return nil
+}
+
+func validatePlaceFilter(filter model.PlaceFilter) error {
+	if b := filter.BBox; b != nil {
+		// minLon > maxLon は ±180° をまたぐ範囲として扱う（緯度は逆転できない）
+		if !geo.ValidCoordinate(b.MinLat, b.MinLon) || !geo.ValidCoordinate(b.MaxLat, b.MaxLon) ||
+			b.MinLat > b.MaxLat {
+			return ErrInvalidBoundingBox
+		}
+	}
+	for _, c := range filter.Categories {
+		if !c.IsValid() {
+			return ErrInvalidCategory
+		}
+	}
+	for _, t := range filter.DisasterTypes {
+		if !t.IsValid() {
+			return ErrInvalidDisaster
+		}
+	}
+	for _, st := range filter.Statuses {
+		if !st.IsValid() {
+			return ErrInvalidStatus
+		}
+	}
+	return nil
+}
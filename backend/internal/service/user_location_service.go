@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+	"zerodelay/internal/geo"
+)
+
+var (
+	ErrLocationNotFound = errors.New("location not found")
+	ErrInvalidLocation  = errors.New("invalid location")
+	ErrTooManyLocations = errors.New("too many locations")
+)
+
+const (
+	// MaxLocationsPerUser limits how many locations a user can register
+	MaxLocationsPerUser = 10
+	// locationShelterRadiusMeters / locationShelterLimit bound the nearest-shelter search per location
+	locationShelterRadiusMeters = 5000
+	locationShelterLimit        = 3
+)
+
+// shelterCategories are the place categories offered as evacuation destinations
+var shelterCategories = []model.PlaceCategory{
+	model.PlaceCategoryEmergencyEvacuationSite,
+	model.PlaceCategoryDesignatedShelter,
+	model.PlaceCategoryWelfareShelter,
+	model.PlaceCategoryEvacuationBuilding,
+}
+
+// UserLocationService handles business logic for users' saved locations
+type UserLocationService struct {
+	locationRepo  repository.UserLocationRepository
+	userRepo      repository.UserRepository
+	hazardService *HazardService
+	placeService  *PlaceService
+}
+
+// NewUserLocationService creates a new saved location service
+func NewUserLocationService(
+	locationRepo repository.UserLocationRepository,
+	userRepo repository.UserRepository,
+	hazardService *HazardService,
+	placeService *PlaceService,
+) *UserLocationService {
+	return &UserLocationService{
+		locationRepo:  locationRepo,
+		userRepo:      userRepo,
+		hazardService: hazardService,
+		placeService:  placeService,
+	}
+}
+
+// ListLocations returns the caller's locations with their risk and nearest suitable shelters
+func (s *UserLocationService) ListLocations(firebaseUID string) ([]model.UserLocationWithRisk, error) {
+	user, err := s.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := s.locationRepo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.UserLocationWithRisk, 0, len(locations))
+	for _, location := range locations {
+		result = append(result, s.withRisk(location))
+	}
+	return result, nil
+}
+
+// GetLocation returns one of the caller's locations with its risk
+func (s *UserLocationService) GetLocation(firebaseUID string, id uint) (*model.UserLocationWithRisk, error) {
+	location, err := s.findOwnedLocation(firebaseUID, id)
+	if err != nil {
+		return nil, err
+	}
+	withRisk := s.withRisk(*location)
+	return &withRisk, nil
+}
+
+// CreateLocation registers a new location for the caller
+func (s *UserLocationService) CreateLocation(firebaseUID string, req *model.UserLocationRequest) (*model.UserLocationWithRisk, error) {
+	user, err := s.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 登録数の上限チェック
+	count, err := s.locationRepo.CountByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxLocationsPerUser {
+		return nil, ErrTooManyLocations
+	}
+
+	// 2. 緯度経度は必須
+	if req.Lat == nil || req.Lon == nil {
+		return nil, fmt.Errorf("%w: lat and lon are required", ErrInvalidLocation)
+	}
+	location := &model.UserLocation{UserID: user.ID, Kind: model.LocationKindOther}
+	if err := applyLocationRequest(location, req); err != nil {
+		return nil, err
+	}
+
+	// 3. 保存
+	if err := s.locationRepo.Create(location); err != nil {
+		return nil, fmt.Errorf("failed to create location: %w", err)
+	}
+
+	withRisk := s.withRisk(*location)
+	return &withRisk, nil
+}
+
+// UpdateLocation partially updates one of the caller's locations
+func (s *UserLocationService) UpdateLocation(firebaseUID string, id uint, req *model.UserLocationRequest) (*model.UserLocationWithRisk, error) {
+	location, err := s.findOwnedLocation(firebaseUID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyLocationRequest(location, req); err != nil {
+		return nil, err
+	}
+	if err := s.locationRepo.Update(location); err != nil {
+		return nil, fmt.Errorf("failed to update location: %w", err)
+	}
+
+	withRisk := s.withRisk(*location)
+	return &withRisk, nil
+}
+
+// DeleteLocation removes one of the caller's locations
+func (s *UserLocationService) DeleteLocation(firebaseUID string, id uint) error {
+	if _, err := s.findOwnedLocation(firebaseUID, id); err != nil {
+		return err
+	}
+	return s.locationRepo.Delete(id)
+}
+
+func (s *UserLocationService) findUser(firebaseUID string) (*model.User, error) {
+	user, err := s.userRepo.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// findOwnedLocation loads a location and hides other users' locations as not found
+func (s *UserLocationService) findOwnedLocation(firebaseUID string, id uint) (*model.UserLocation, error) {
+	user, err := s.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := s.locationRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLocationNotFound
+		}
+		return nil, err
+	}
+	if location.UserID != user.ID {
+		return nil, ErrLocationNotFound
+	}
+	return location, nil
+}
+
+// withRisk computes the hazard summary and nearest suitable shelters of a location.
+// 計算に失敗しても地点情報自体は返す（リスクは null になる）
+func (s *UserLocationService) withRisk(location model.UserLocation) model.UserLocationWithRisk {
+	result := model.UserLocationWithRisk{
+		UserLocation:    location,
+		NearestShelters: []model.PlaceWithDistance{},
+	}
+
+	assessment, err := s.hazardService.AssessPoint(location.Lat, location.Lon)
+	if err != nil {
+		log.Printf("[WARN] Failed to assess hazards for location %d: %v", location.ID, err)
+	} else {
+		result.Risk = &model.LocationRisk{
+			Summary:            assessment.Summary,
+			RecommendedActions: assessment.RecommendedActions,
+		}
+	}
+
+	shelters, err := s.placeService.NearbyPlaces(model.NearbyQuery{
+		Lat:          location.Lat,
+		Lon:          location.Lon,
+		RadiusMeters: locationShelterRadiusMeters,
+		Limit:        locationShelterLimit,
+		Filter: model.PlaceFilter{
+			Categories:    shelterCategories,
+			DisasterTypes: requiredDisasterTypes(assessment),
+		},
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to find shelters near location %d: %v", location.ID, err)
+	} else if shelters != nil {
+		result.NearestShelters = shelters
+	}
+
+	return result
+}
+
+// requiredDisasterTypes returns the disaster types a shelter must be certified for at this location.
+// 水害を主眼とするため、該当ハザードがなくても洪水対応を条件とする
+func requiredDisasterTypes(assessment *model.HazardAssessment) []model.DisasterType {
+	types := []model.DisasterType{model.DisasterFlood}
+	if assessment != nil && assessment.Summary.LandslideWarningArea {
+		types = append(types, model.DisasterLandslide)
+	}
+	return types
+}
+
+// applyLocationRequest copies the sent fields and validates the result
+func applyLocationRequest(location *model.UserLocation, req *model.UserLocationRequest) error {
+	if req.Label != nil {
+		location.Label = strings.TrimSpace(*req.Label)
+	}
+	if req.Kind != nil {
+		location.Kind = *req.Kind
+	}
+	if req.Address != nil {
+		location.Address = strings.TrimSpace(*req.Address)
+	}
+	if req.Lat != nil {
+		location.Lat = *req.Lat
+	}
+	if req.Lon != nil {
+		location.Lon = *req.Lon
+	}
+
+	if location.Label == "" {
+		return fmt.Errorf("%w: label is required", ErrInvalidLocation)
+	}
+	if !location.Kind.IsValid() {
+		return fmt.Errorf("%w: unknown kind", ErrInvalidLocation)
+	}
+	if !geo.ValidCoordinate(location.Lat, location.Lon) {
+		return fmt.Errorf("%w: invalid coordinates", ErrInvalidLocation)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"zerodelay/internal/domain/model"
+	"zerodelay/internal/domain/repository"
+)
+
+// ErrNoAuthUsers is returned when the auth provider has no users but the users table does.
+// 接続先プロジェクトの設定ミスで全ユーザーを孤立扱いにしないよう、処理を中止する
+var ErrNoAuthUsers = errors.New("auth provider returned no users; check the Firebase project")
+
+// provisionGracePeriod leaves recently created auth users alone.
+// サインアップ処理中（Firebase ユーザー作成後、行の作成前）のユーザーと競合しないようにする
+const provisionGracePeriod = 10 * time.Minute
+
+// UserReconciliationService keeps the users table in step with the auth provider's users.
+// サインアップ時のロールバック失敗や Firebase コンソールでの変更で生じたずれを解消する
+type UserReconciliationService struct {
+	authRepo repository.AuthRepository
+	userRepo repository.UserRepository
+}
+
+// NewUserReconciliationService creates a new reconciliation service
+func NewUserReconciliationService(authRepo repository.AuthRepository, userRepo repository.UserRepository) *UserReconciliationService {
+	return &UserReconciliationService{
+		authRepo: authRepo,
+		userRepo: userRepo,
+	}
+}
+
+// Reconcile diffs the auth provider's users against the users table.
+// 認証プロバイダーにだけ存在するユーザーは行を作成し、メールアドレスは認証プロバイダー側に合わせる。
+// users にだけ存在する行は削除せず orphaned_at を設定する（関連データの確認は管理者が行う）
+func (s *UserReconciliationService) Reconcile(ctx context.Context, opts model.ReconcileOptions) (*model.ReconcileReport, error) {
+	// 先に users を読む。後から認証プロバイダーを読むため、サインアップ中に作成された行が
+	// 認証プロバイダー側の一覧に無いという理由で孤立扱いされることはない
+	dbUsers, err := s.userRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	authUsers, err := s.authRepo.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(authUsers) == 0 && len(dbUsers) > 0 {
+		return nil, ErrNoAuthUsers
+	}
+
+	report := &model.ReconcileReport{
+		DryRun:    opts.DryRun,
+		AuthUsers: len(authUsers),
+		DBUsers:   len(dbUsers),
+		Entries:   []model.ReconcileEntry{},
+	}
+
+	byUID := make(map[string]*model.User, len(dbUsers))
+	emailOwners := make(map[string]string, len(dbUsers))
+	for i := range dbUsers {
+		byUID[dbUsers[i].FirebaseUID] = &dbUsers[i]
+		if dbUsers[i].Email != "" {
+			emailOwners[dbUsers[i].Email] = dbUsers[i].FirebaseUID
+		}
+	}
+	seen := make(map[string]bool, len(authUsers))
+	now := time.Now()
+
+	// 1. 認証プロバイダー側のユーザーを基準に、行の作成・メールアドレスの同期・孤立フラグの解除
+	for _, authUser := range authUsers {
+		seen[authUser.UID] = true
+		dbUser, ok := byUID[authUser.UID]
+		if !ok {
+			if now.Sub(authUser.CreatedAt) < provisionGracePeriod {
+				continue
+			}
+			s.provision(report, authUser, emailOwners, opts.DryRun)
+			continue
+		}
+
+		// 変更した列だけを更新し、同時に行われたプロフィール編集などを上書きしない
+		columns := map[string]interface{}{}
+		if dbUser.OrphanedAt != nil {
+			dbUser.OrphanedAt = nil
+			columns["orphaned_at"] = nil
+			report.Restored++
+			report.Entries = append(report.Entries, model.ReconcileEntry{
+				FirebaseUID: authUser.UID, Email: dbUser.Email, Action: model.ReconcileRestore,
+			})
+		}
+		// ゲストからの登録後に行の更新が失敗した場合はゲストフラグを外す
+		if dbUser.IsGuest && !authUser.Anonymous {
+			dbUser.IsGuest = false
+			columns["is_guest"] = false
+		}
+		if authUser.Email != "" && authUser.Email != dbUser.Email {
+			if owner, taken := emailOwners[authUser.Email]; taken && owner != authUser.UID {
+				report.Skipped++
+				report.Entries = append(report.Entries, model.ReconcileEntry{
+					FirebaseUID: authUser.UID, Email: authUser.Email, Action: model.ReconcileSkip,
+					Reason: fmt.Sprintf("email is used by another user (%s)", owner),
+				})
+			} else {
+				report.EmailsSynced++
+				report.Entries = append(report.Entries, model.ReconcileEntry{
+					FirebaseUID: authUser.UID, Email: authUser.Email, Action: model.ReconcileSyncEmail,
+					Reason: fmt.Sprintf("was %s", dbUser.Email),
+				})
+				if dbUser.Email != "" {
+					delete(emailOwners, dbUser.Email)
+				}
+				emailOwners[authUser.Email] = authUser.UID
+				dbUser.Email = authUser.Email
+				columns["email"] = authUser.Email
+			}
+		}
+		if len(columns) > 0 && !opts.DryRun {
+			if err := s.userRepo.UpdateColumns(dbUser.ID, columns); err != nil {
+				log.Printf("[ERROR] Reconcile: failed to update user %s: %v", authUser.UID, err)
+			}
+		}
+	}
+
+	// 2. users にだけ存在する行に孤立フラグを立てる
+	for i := range dbUsers {
+		dbUser := &dbUsers[i]
+		if seen[dbUser.FirebaseUID] || dbUser.OrphanedAt != nil {
+			continue
+		}
+		report.Orphaned++
+		report.Entries = append(report.Entries, model.ReconcileEntry{
+			FirebaseUID: dbUser.FirebaseUID, Email: dbUser.Email, Action: model.ReconcileOrphan,
+			Reason: "not found in the auth provider",
+		})
+		if opts.DryRun {
+			continue
+		}
+		dbUser.OrphanedAt = &now
+		if err := s.userRepo.UpdateColumns(dbUser.ID, map[string]interface{}{"orphaned_at": now}); err != nil {
+			log.Printf("[ERROR] Reconcile: failed to flag orphaned user %s: %v", dbUser.FirebaseUID, err)
+		}
+	}
+
+	return report, nil
+}
+
+// provision creates the missing users row of an auth user
+func (s *UserReconciliationService) provision(report *model.ReconcileReport, authUser model.AuthUser, emailOwners map[string]string, dryRun bool) {
+	skip := func(reason string) {
+		report.Skipped++
+		report.Entries = append(report.Entries, model.ReconcileEntry{
+			FirebaseUID: authUser.UID, Email: authUser.Email, Action: model.ReconcileSkip, Reason: reason,
+		})
+	}
+
+	// ゲスト（匿名ユーザー）はメールアドレスなしで行を作成する
+	if authUser.Email == "" && !authUser.Anonymous {
+		skip("auth user has no email")
+		return
+	}
+	if owner, taken := emailOwners[authUser.Email]; taken && authUser.Email != "" {
+		skip(fmt.Sprintf("email is used by another user (%s)", owner))
+		return
+	}
+
+	if !dryRun {
+		user := &model.User{
+			FirebaseUID: authUser.UID,
+			Email:       authUser.Email,
+			Role:        model.RoleResident,
+			IsGuest:     authUser.Anonymous,
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			log.Printf("[ERROR] Reconcile: failed to provision user %s: %v", authUser.UID, err)
+			skip(fmt.Sprintf("failed to create row: %v", err))
+			return
+		}
+	}
+	if authUser.Email != "" {
+		emailOwners[authUser.Email] = authUser.UID
+	}
+	report.Provisioned++
+	report.Entries = append(report.Entries, model.ReconcileEntry{
+		FirebaseUID: authUser.UID, Email: authUser.Email, Action: model.ReconcileProvision,
+	})
+}
+
+// RunPeriodically reconciles every interval until ctx is cancelled
+func (s *UserReconciliationService) RunPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Reconcile(ctx, model.ReconcileOptions{})
+			if err != nil {
+				log.Printf("[ERROR] User reconciliation failed: %v", err)
+				continue
+			}
+			log.Printf("[INFO] User reconciliation: %d auth users, %d rows, %d provisioned, %d emails synced, %d orphaned, %d restored, %d skipped",
+				report.AuthUsers, report.DBUsers, report.Provisioned, report.EmailsSynced, report.Orphaned, report.Restored, report.Skipped)
+			for _, entry := range report.Entries {
+				if entry.Action == model.ReconcileSkip {
+					log.Printf("[WARN] User reconciliation skipped %s (%s): %s", entry.FirebaseUID, entry.Email, entry.Reason)
+				}
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -11,23 +12,35 @@ import (
 	"zerodelay/internal/domain/repository"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound   = errors.New("user not found")
+	ErrInvalidRole    = errors.New("invalid role")
+	ErrRoleSelfChange = errors.New("cannot change own role")
+)
 
 // UserService handles business logic for users
 type UserService struct {
-	userRepo repository.UserRepository
-	authRepo repository.AuthRepository
+	userRepo     repository.UserRepository
+	authRepo     repository.AuthRepository
+	emailChanges *EmailChangeService
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository, authRepo repository.AuthRepository) *UserService {
+func NewUserService(userRepo repository.UserRepository, authRepo repository.AuthRepository, emailChanges *EmailChangeService) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		authRepo: authRepo,
+		userRepo:     userRepo,
+		authRepo:     authRepo,
+		emailChanges: emailChanges,
 	}
 }
 
 func (s *UserService) CreateUser(user *model.User) error {
+	if user.Role == "" {
+		user.Role = model.RoleResident
+	}
+	if !user.Role.IsValid() {
+		return ErrInvalidRole
+	}
 	return s.userRepo.Create(user)
 }
 
@@ -42,34 +55,110 @@ func (s *UserService) GetUser(id uint) (*model.User, error) {
 	return user, nil
 }
 
+// GetUserByFirebaseUID returns the user linked to a Firebase UID
+func (s *UserService) GetUserByFirebaseUID(firebaseUID string) (*model.User, error) {
+	user, err := s.userRepo.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
 func (s *UserService) GetAllUsers() ([]model.User, error) {
 	return s.userRepo.FindAll()
 }
 
 func (s *UserService) UpdateUser(user *model.User) error {
 	// Check if user exists
-	_, err := s.userRepo.FindByID(user.ID)
+	existing, err := s.userRepo.FindByID(user.ID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrUserNotFound
 		}
 		return err
 	}
+	// Firebase UID は変更不可、ロールは UpdateRole でのみ変更できる
+	// メールアドレスは確認フロー（POST /api/v1/users/me/email）でのみ変更できる
+	// ゲストかどうかは UpgradeGuest でのみ変わる
+	user.FirebaseUID = existing.FirebaseUID
+	user.Role = existing.Role
+	user.Email = existing.Email
+	user.OrphanedAt = existing.OrphanedAt
+	user.IsGuest = existing.IsGuest
 	return s.userRepo.Update(user)
 }
 
-func (s *UserService) DeleteUser(id uint) error {
+// UpdateRole changes a user's role. Administrators cannot change their own role
+// so that the last administrator cannot lock everyone out by accident.
+func (s *UserService) UpdateRole(actorFirebaseUID string, id uint, role model.UserRole) (*model.User, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.FirebaseUID == actorFirebaseUID {
+		return nil, ErrRoleSelfChange
+	}
+
+	user.Role = role
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+	return user, nil
+}
+
+// DeleteUser deletes a user's row. The auth provider account is kept unless deleteAuthAccount is true,
+// in which case both are deleted together like DeleteAccount.
+func (s *UserService) DeleteUser(ctx context.Context, id uint, deleteAuthAccount bool) error {
 	// Check if user exists
-	_, err := s.userRepo.FindByID(id)
+	user, err := s.userRepo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrUserNotFound
 		}
 		return err
 	}
+	if deleteAuthAccount {
+		return s.deleteAccount(ctx, user)
+	}
 	return s.userRepo.Delete(id)
 }
 
+// DeleteAccount deletes the caller's own account from both PostgreSQL and Firebase
+func (s *UserService) DeleteAccount(ctx context.Context, firebaseUID string) error {
+	user, err := s.userRepo.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		// DBに行がなくてもFirebase側のアカウントは削除する（前回の削除が途中で失敗した場合など）
+		return s.authRepo.DeleteUser(ctx, firebaseUID)
+	}
+	return s.deleteAccount(ctx, user)
+}
+
+// deleteAccount deletes the DB row and the Firebase account together.
+// Firebase の削除はDBトランザクション内で行い、失敗した場合はDBの削除もロールバックする。
+// Firebase 側で既に削除済みの場合は成功として扱うため、何度再試行しても安全。
+func (s *UserService) deleteAccount(ctx context.Context, user *model.User) error {
+	err := s.userRepo.DeleteInTransaction(user.ID, func() error {
+		return s.authRepo.DeleteUser(ctx, user.FirebaseUID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
+
 func (s *UserService) UpdateProfile(ctx context.Context, firebaseUID string, req *model.UpdateProfileRequest) (*model.User, error) {
 	// 1. FirebaseUIDでユーザーを取得
 	user, err := s.userRepo.FindByFirebaseUID(firebaseUID)
@@ -91,12 +180,11 @@ func (s *UserService) UpdateProfile(ctx context.Context, firebaseUID string, req
 		user.Sex = *req.Sex
 	}
 
-	// 3. Email更新時はFirebaseも同期
-	if req.Email != nil && *req.Email != user.Email {
-		if err := s.authRepo.UpdateEmail(ctx, firebaseUID, *req.Email); err != nil {
-			return nil, fmt.Errorf("failed to update email in Firebase: %w", err)
+	// 3. Email変更は新しいアドレスでの確認後に反映する（ここでは確認メールを送るだけ）
+	if req.Email != nil && !strings.EqualFold(strings.TrimSpace(*req.Email), user.Email) {
+		if _, err := s.emailChanges.RequestChange(ctx, firebaseUID, *req.Email); err != nil {
+			return nil, err
 		}
-		user.Email = *req.Email
 	}
 
 	// 4. Setting更新時はマージ
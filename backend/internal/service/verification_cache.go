@@ -0,0 +1,62 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// emailVerificationCacheTTL is how long a looked-up verification state is trusted.
+// 確認直後のユーザーが長く待たされない程度に短くする
+const emailVerificationCacheTTL = time.Minute
+
+// emailVerificationCache is a small in-memory cache of Firebase email verification state per UID
+type emailVerificationCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]verificationEntry
+}
+
+type verificationEntry struct {
+	verified  bool
+	expiresAt time.Time
+}
+
+func newEmailVerificationCache(ttl time.Duration) *emailVerificationCache {
+	return &emailVerificationCache{
+		ttl:     ttl,
+		entries: make(map[string]verificationEntry),
+	}
+}
+
+// get returns the cached state and whether it is still fresh
+func (c *emailVerificationCache) get(uid string) (verified bool, ok bool) {
+	c.mu.RLock()
+	entry, found := c.entries[uid]
+	c.mu.RUnlock()
+	if !found || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+	return entry.verified, true
+}
+
+func (c *emailVerificationCache) set(uid string, verified bool) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 期限切れのエントリを掃除してメモリが増え続けないようにする
+	if len(c.entries) >= 10000 {
+		for key, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	c.entries[uid] = verificationEntry{verified: verified, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *emailVerificationCache) delete(uid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, uid)
+}
@@ -0,0 +1,4 @@
+-- Rollback: drop every table of the baseline (ALL DATA IS LOST)
+-- migrate down は -drop-baseline を指定しない限りこのファイルを実行しない
+DROP TABLE IF EXISTS "place";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,36 @@
+-- Baseline: the schema created by GORM AutoMigrate before versioned migrations were introduced.
+-- 既存のデータベースはそのまま取り込めるよう、すべて IF NOT EXISTS で作成する
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "firebase_uid" text NOT NULL,
+    "email" text NOT NULL,
+    "name" text,
+    "name_kana" text,
+    "old" integer,
+    "sex" text,
+    "setting" json,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "place" (
+    "id" bigserial,
+    "name" text,
+    "name_kana" text,
+    "address" text,
+    "lat" text,
+    "lon" text,
+    "url" text,
+    "tel" text,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_firebase_uid" ON "users" ("firebase_uid");
+
+-- 014_guest_accounts 適用後のスキーマ（ゲストの空メールアドレスが重複しうる）には作成しない
+DO $$
+BEGIN
+    IF to_regclass('idx_users_email_registered') IS NULL THEN
+        CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+    END IF;
+END $$;
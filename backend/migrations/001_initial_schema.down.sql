@@ -1,3 +0,0 @@
--- Rollback: Drop tables in reverse order
-DROP TABLE IF EXISTS place;
-DROP TABLE IF EXISTS users;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS "idx_place_lat_lon";
+
+ALTER TABLE "place" ALTER COLUMN "lat" DROP NOT NULL;
+ALTER TABLE "place" ALTER COLUMN "lon" DROP NOT NULL;
+ALTER TABLE "place" ALTER COLUMN "lat" TYPE text USING "lat"::text;
+ALTER TABLE "place" ALTER COLUMN "lon" TYPE text USING "lon"::text;
+
+-- 退避した行を戻す（座標は元の文字列のまま）
+INSERT INTO "place" ("id", "name", "name_kana", "address", "lat", "lon", "url", "tel")
+SELECT "id", "name", "name_kana", "address", "lat", "lon", "url", "tel" FROM "place_invalid_coordinates";
+
+DROP TABLE IF EXISTS "place_invalid_coordinates";
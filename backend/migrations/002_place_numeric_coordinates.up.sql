@@ -0,0 +1,44 @@
+-- place.lat / lon: text -> double precision NOT NULL (nearby search and bbox filter)
+-- 数値に変換できない・範囲外の座標を持つ行は place_invalid_coordinates に退避して place から削除する。
+-- 退避した行は管理者が座標を修正して登録し直す（検索結果に (0, 0) や NULL の座標が混ざらないようにする）
+
+CREATE TABLE IF NOT EXISTS "place_invalid_coordinates" (
+    "id" bigint,
+    "name" text,
+    "name_kana" text,
+    "address" text,
+    "lat" text,
+    "lon" text,
+    "url" text,
+    "tel" text,
+    "quarantined_at" timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY ("id")
+);
+
+-- 1. 数値として読めない座標（空文字・NULL を含む）
+-- lat::text を経由するため、すでに double precision の列にも適用できる
+WITH invalid AS (
+    DELETE FROM "place"
+    WHERE COALESCE(TRIM("lat"::text), '') !~ '^[-+]?([0-9]+([.][0-9]*)?|[.][0-9]+)([eE][-+]?[0-9]+)?$'
+       OR COALESCE(TRIM("lon"::text), '') !~ '^[-+]?([0-9]+([.][0-9]*)?|[.][0-9]+)([eE][-+]?[0-9]+)?$'
+    RETURNING "id", "name", "name_kana", "address", "lat"::text, "lon"::text, "url", "tel"
+)
+INSERT INTO "place_invalid_coordinates" ("id", "name", "name_kana", "address", "lat", "lon", "url", "tel")
+SELECT * FROM invalid;
+
+ALTER TABLE "place" ALTER COLUMN "lat" TYPE double precision USING TRIM("lat"::text)::double precision;
+ALTER TABLE "place" ALTER COLUMN "lon" TYPE double precision USING TRIM("lon"::text)::double precision;
+
+-- 2. WGS84 の範囲外の座標
+WITH out_of_range AS (
+    DELETE FROM "place"
+    WHERE "lat" NOT BETWEEN -90 AND 90 OR "lon" NOT BETWEEN -180 AND 180
+    RETURNING "id", "name", "name_kana", "address", "lat"::text, "lon"::text, "url", "tel"
+)
+INSERT INTO "place_invalid_coordinates" ("id", "name", "name_kana", "address", "lat", "lon", "url", "tel")
+SELECT * FROM out_of_range;
+
+ALTER TABLE "place" ALTER COLUMN "lat" SET NOT NULL;
+ALTER TABLE "place" ALTER COLUMN "lon" SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS "idx_place_lat_lon" ON "place" ("lat", "lon");
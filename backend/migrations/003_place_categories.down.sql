@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "idx_place_category";
+
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_volcano";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_inland_water";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_large_fire";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_tsunami";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_earthquake";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_storm_surge";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_landslide";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "safe_for_flood";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "category";
@@ -0,0 +1,13 @@
+-- place: shelter category and the hazards each place is safe for
+-- 既存の行は DEFAULT の値（other / false）で埋まる
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "category" text NOT NULL DEFAULT 'other';
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_flood" boolean NOT NULL DEFAULT false;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_landslide" boolean NOT NULL DEFAULT false;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_storm_surge" boolean NOT NULL DEFAULT false;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_earthquake" boolean NOT NULL DEFAULT false;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_tsunami" boolean NOT NULL DEFAULT false;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_large_fire" boolean NOT NULL DEFAULT false;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_inland_water" boolean NOT NULL DEFAULT false;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "safe_for_volcano" boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS "idx_place_category" ON "place" ("category");
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "place_occupancy_history";
+
+DROP INDEX IF EXISTS "idx_place_status";
+
+ALTER TABLE "place" DROP COLUMN IF EXISTS "status_updated_at";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "status";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "occupancy";
+ALTER TABLE "place" DROP COLUMN IF EXISTS "capacity";
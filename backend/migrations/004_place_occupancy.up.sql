@@ -0,0 +1,20 @@
+-- place: capacity, live occupancy and open/closed status, plus their history
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "capacity" integer NOT NULL DEFAULT 0;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "occupancy" integer NOT NULL DEFAULT 0;
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT 'closed';
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "status_updated_at" timestamptz;
+
+CREATE INDEX IF NOT EXISTS "idx_place_status" ON "place" ("status");
+
+CREATE TABLE IF NOT EXISTS "place_occupancy_history" (
+    "id" bigserial,
+    "place_id" bigint NOT NULL,
+    "status" text NOT NULL,
+    "capacity" integer NOT NULL,
+    "occupancy" integer NOT NULL,
+    "updated_by" text,
+    "recorded_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_place_occupancy_history_place_time" ON "place_occupancy_history" ("place_id", "recorded_at");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_place_external_id";
+
+ALTER TABLE "place" DROP COLUMN IF EXISTS "external_id";
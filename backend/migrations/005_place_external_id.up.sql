@@ -0,0 +1,5 @@
+-- place.external_id: identifier of the imported dataset row (upsert key of the CSV/GeoJSON import)
+-- 既存の行は NULL のため、一意インデックスに衝突しない
+ALTER TABLE "place" ADD COLUMN IF NOT EXISTS "external_id" text;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_place_external_id" ON "place" ("external_id");
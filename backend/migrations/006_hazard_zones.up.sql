@@ -0,0 +1,21 @@
+-- hazard_zones: imported hazard map polygons with their bounding box
+CREATE TABLE IF NOT EXISTS "hazard_zones" (
+    "id" bigserial,
+    "kind" text NOT NULL,
+    "source" text NOT NULL,
+    "source_river" text,
+    "scenario" text,
+    "depth_rank" integer NOT NULL DEFAULT 0,
+    "geometry" jsonb NOT NULL,
+    "min_lon" double precision NOT NULL,
+    "min_lat" double precision NOT NULL,
+    "max_lon" double precision NOT NULL,
+    "max_lat" double precision NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_hazard_zones_kind" ON "hazard_zones" ("kind");
+CREATE INDEX IF NOT EXISTS "idx_hazard_zones_source" ON "hazard_zones" ("source");
+CREATE INDEX IF NOT EXISTS "idx_hazard_zones_scenario" ON "hazard_zones" ("scenario");
+CREATE INDEX IF NOT EXISTS "idx_hazard_zones_bbox" ON "hazard_zones" ("min_lat", "max_lat", "min_lon", "max_lon");
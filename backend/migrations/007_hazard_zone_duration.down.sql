@@ -0,0 +1,2 @@
+ALTER TABLE "hazard_zones" DROP COLUMN IF EXISTS "zone_type";
+ALTER TABLE "hazard_zones" DROP COLUMN IF EXISTS "duration_rank";
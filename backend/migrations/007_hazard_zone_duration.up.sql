@@ -0,0 +1,3 @@
+-- hazard_zones: flood duration rank and landslide zone type (point risk assessment)
+ALTER TABLE "hazard_zones" ADD COLUMN IF NOT EXISTS "duration_rank" integer NOT NULL DEFAULT 0;
+ALTER TABLE "hazard_zones" ADD COLUMN IF NOT EXISTS "zone_type" text;
@@ -0,0 +1,16 @@
+-- user_locations: places registered by a user (home, work, ...)
+CREATE TABLE IF NOT EXISTS "user_locations" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "label" text NOT NULL,
+    "kind" text NOT NULL DEFAULT 'other',
+    "address" text,
+    "lat" double precision NOT NULL,
+    "lon" double precision NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_locations_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "idx_user_locations_user_id" ON "user_locations" ("user_id");
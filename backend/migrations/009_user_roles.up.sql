@@ -0,0 +1,3 @@
+-- users.role: resident / staff / admin
+-- 既存のユーザーは resident になる
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" text NOT NULL DEFAULT 'resident';
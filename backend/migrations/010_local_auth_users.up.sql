@@ -0,0 +1,20 @@
+-- local_auth_users: accounts of the built-in auth provider (AUTH_PROVIDER=local)
+CREATE TABLE IF NOT EXISTS "local_auth_users" (
+    "uid" text,
+    "email" text NOT NULL,
+    "password_hash" text NOT NULL,
+    "email_verified" boolean NOT NULL DEFAULT false,
+    "disabled" boolean NOT NULL DEFAULT false,
+    "tokens_valid_after" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("uid")
+);
+
+-- 014_guest_accounts 適用後のスキーマ（ゲストの空メールアドレスが重複しうる）には作成しない
+DO $$
+BEGIN
+    IF to_regclass('idx_local_auth_users_email_registered') IS NULL THEN
+        CREATE UNIQUE INDEX IF NOT EXISTS "idx_local_auth_users_email" ON "local_auth_users" ("email");
+    END IF;
+END $$;
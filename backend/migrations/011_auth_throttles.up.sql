@@ -0,0 +1,14 @@
+-- auth_throttles: rate limit and lockout counters of the auth endpoints
+CREATE TABLE IF NOT EXISTS "auth_throttles" (
+    "key" text,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "window_start" timestamptz NOT NULL,
+    "failures" bigint NOT NULL DEFAULT 0,
+    "last_failure_at" timestamptz,
+    "locked_until" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("key")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_auth_throttles_locked_until" ON "auth_throttles" ("locked_until");
+CREATE INDEX IF NOT EXISTS "idx_auth_throttles_updated_at" ON "auth_throttles" ("updated_at");
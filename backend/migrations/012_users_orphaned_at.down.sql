@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_users_orphaned_at";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "orphaned_at";
@@ -0,0 +1,4 @@
+-- users.orphaned_at: set when the auth provider account no longer exists
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "orphaned_at" timestamptz;
+
+CREATE INDEX IF NOT EXISTS "idx_users_orphaned_at" ON "users" ("orphaned_at");
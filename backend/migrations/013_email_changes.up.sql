@@ -0,0 +1,14 @@
+-- email_changes: pending email address changes waiting for confirmation
+CREATE TABLE IF NOT EXISTS "email_changes" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "new_email" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_email_changes_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_email_changes_user_id" ON "email_changes" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_email_changes_token_hash" ON "email_changes" ("token_hash");
@@ -0,0 +1,12 @@
+-- ゲストが残っていると空文字のメールアドレスが重複して一意インデックスを作れないため、先に削除する
+DELETE FROM "users" WHERE "is_guest";
+DELETE FROM "local_auth_users" WHERE "anonymous";
+
+DROP INDEX IF EXISTS "idx_users_email_registered";
+DROP INDEX IF EXISTS "idx_local_auth_users_email_registered";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_local_auth_users_email" ON "local_auth_users" ("email");
+
+ALTER TABLE "local_auth_users" DROP COLUMN IF EXISTS "anonymous";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "is_guest";
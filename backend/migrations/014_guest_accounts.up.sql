@@ -0,0 +1,10 @@
+-- Guest (anonymous) accounts: users.is_guest / local_auth_users.anonymous
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "is_guest" boolean NOT NULL DEFAULT false;
+ALTER TABLE "local_auth_users" ADD COLUMN IF NOT EXISTS "anonymous" boolean NOT NULL DEFAULT false;
+
+-- ゲストはメールアドレスが空のため、メールアドレスの一意制約は空文字を除いた部分インデックスにする
+DROP INDEX IF EXISTS "idx_users_email";
+DROP INDEX IF EXISTS "idx_local_auth_users_email";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email_registered" ON "users" ("email") WHERE email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_local_auth_users_email_registered" ON "local_auth_users" ("email") WHERE email <> '';
@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned SQL migrations.
+// ファイル名は <version>_<name>.up.sql / <version>_<name>.down.sql（`go run ./cmd/migrate create <name>` で作成する）
+package migrations
+
+import "embed"
+
+// FS holds every *.sql file of this directory
+//
+//go:embed *.sql
+var FS embed.FS